@@ -20,6 +20,11 @@ func main() {
 // If the user wants to then a bug report is sent.
 func handleError(err error) {
 	if err != nil {
+		if exitErr, ok := err.(*secrethub.ExitStatusError); ok {
+			// The command started by secrethub has already reported why it failed,
+			// so secrethub only exits in the same way as the command did.
+			exitErr.Exit()
+		}
 		fmt.Fprintf(os.Stderr, "Encountered an error: %s\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,119 @@
+package masker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+)
+
+// Encoding is an encoding in which secrets are masked in addition to their raw form.
+// This prevents secrets from leaking when they are logged in an encoded form,
+// e.g. as part of a base64 encoded HTTP header.
+type Encoding string
+
+// The encodings in which secrets can be masked.
+const (
+	EncodingBase64    Encoding = "base64"
+	EncodingBase64URL Encoding = "base64url"
+	EncodingHex       Encoding = "hex"
+	EncodingURL       Encoding = "url"
+	EncodingJSON      Encoding = "json"
+)
+
+// Encodings contains all encodings in which secrets can be masked.
+var Encodings = []Encoding{
+	EncodingBase64,
+	EncodingBase64URL,
+	EncodingHex,
+	EncodingURL,
+	EncodingJSON,
+}
+
+// sequences returns the sequences in which the secret appears when it is encoded with the encoding.
+// The number of sequences does not depend on the secret and their length is linear in the length
+// of the secret, so masking the encoded forms only adds a constant factor to the masking overhead.
+func (e Encoding) sequences(secret []byte) [][]byte {
+	switch e {
+	case EncodingBase64:
+		return base64Sequences(base64.RawStdEncoding, secret)
+	case EncodingBase64URL:
+		return base64Sequences(base64.RawURLEncoding, secret)
+	case EncodingHex:
+		lower := []byte(hex.EncodeToString(secret))
+		return [][]byte{lower, bytes.ToUpper(lower)}
+	case EncodingURL:
+		return [][]byte{
+			[]byte(url.QueryEscape(string(secret))),
+			[]byte(url.PathEscape(string(secret))),
+		}
+	case EncodingJSON:
+		return jsonSequences(secret)
+	}
+	return nil
+}
+
+// base64Sequences returns the base64 encoded forms of the secret. When the secret is encoded as part of
+// a larger value, its encoded form depends on its offset in that value modulo 3. So a sequence is returned
+// for each of those offsets, containing only the characters that are fully determined by the secret.
+func base64Sequences(encoding *base64.Encoding, secret []byte) [][]byte {
+	res := make([][]byte, 0, 3)
+	for offset := 0; offset < 3; offset++ {
+		data := make([]byte, offset+len(secret))
+		copy(data[offset:], secret)
+
+		encoded := make([]byte, encoding.EncodedLen(len(data)))
+		encoding.Encode(encoded, data)
+
+		// Every character encodes 6 bits, so skip the characters that contain bits of the preceding
+		// bytes and drop the last character if it contains bits of the following bytes.
+		start := (8*offset + 5) / 6
+		end := 8 * len(data) / 6
+		if end > start {
+			res = append(res, encoded[start:end])
+		}
+	}
+	return res
+}
+
+// jsonSequences returns the secret as it appears in a JSON string, both with and without HTML escaping.
+func jsonSequences(secret []byte) [][]byte {
+	escaped, err := json.Marshal(string(secret))
+	if err != nil {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(string(secret))
+	if err != nil {
+		return nil
+	}
+
+	unescaped := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+
+	// Strip the quotes of the JSON strings.
+	return [][]byte{
+		escaped[1 : len(escaped)-1],
+		unescaped[1 : len(unescaped)-1],
+	}
+}
+
+// encodedSequences returns the distinct sequences of the secret in the given encodings,
+// excluding the secret itself and empty sequences.
+func encodedSequences(secret []byte, encodings []Encoding) [][]byte {
+	seen := map[string]bool{string(secret): true}
+	var res [][]byte
+	for _, encoding := range encodings {
+		for _, sequence := range encoding.sequences(secret) {
+			if len(sequence) == 0 || seen[string(sequence)] {
+				continue
+			}
+			seen[string(sequence)] = true
+			res = append(res, sequence)
+		}
+	}
+	return res
+}
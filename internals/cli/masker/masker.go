@@ -2,6 +2,8 @@ package masker
 
 import (
 	"io"
+	"regexp"
+	"sync"
 	"time"
 )
 
@@ -17,7 +19,11 @@ import (
 // 4. After everything has been written to the io.Writers, flush all buffers using Stop()
 type Masker struct {
 	bufferDelay time.Duration
-	sequences   [][]byte
+	sequences   []labeledSequence
+	patterns    []*regexp.Regexp
+	encodings   []Encoding
+	streams     []*stream
+	streamsLock sync.Mutex
 	frames      chan frame
 	stopChan    chan struct{}
 	err         error
@@ -35,6 +41,13 @@ type Options struct {
 	// Defaults to 50ms if not set.
 	BufferDelay time.Duration
 
+	// Encodings are the encodings in which the sequences are masked in addition to their raw form.
+	Encodings []Encoding
+
+	// Patterns are regular expressions of which all matches are masked, e.g. the format of an access key.
+	// In the report of the masker, the matches of a pattern are labeled with the regular expression.
+	Patterns []*regexp.Regexp
+
 	// FrameBufferLength is the number of frames that can be in the buffer simultaneously.
 	// If the frame buffer is full, writing to a stream blocks until there is space.
 	FrameBufferLength int
@@ -44,11 +57,13 @@ type Options struct {
 func New(sequences [][]byte, opts *Options) *Masker {
 	masker := &Masker{
 		bufferDelay: time.Millisecond * 50,
-		sequences:   sequences,
+		sequences:   labelSequences("", sequences),
 		stopChan:    make(chan struct{}),
 	}
 	frameChanlength := 1024
 	if opts != nil {
+		masker.encodings = opts.Encodings
+		masker.patterns = opts.Patterns
 		if opts.DisableBuffer {
 			masker.bufferDelay = 0
 			frameChanlength = 0
@@ -69,13 +84,45 @@ func New(sequences [][]byte, opts *Options) *Masker {
 
 // AddStream takes in an io.Writer to mask secrets on and returns an io.Writer that has secrets on its output masked.
 func (m *Masker) AddStream(w io.Writer) io.Writer {
-	s := stream{
+	return m.AddNamedStream("", w)
+}
+
+// AddNamedStream adds a stream in the same way as AddStream does.
+// The masks applied to the stream are reported with the given name in the report of the masker.
+func (m *Masker) AddNamedStream(name string, w io.Writer) io.Writer {
+	m.streamsLock.Lock()
+	defer m.streamsLock.Unlock()
+
+	matcher := newMatcher(m.sequences, m.encodings)
+	matcher.addPatterns(m.patterns...)
+
+	s := &stream{
+		name:          name,
 		dest:          w,
 		registerFrame: m.registerFrame,
 		matches:       matches{},
-		matcher:       newMatcher(m.sequences),
+		matcher:       matcher,
+	}
+	m.streams = append(m.streams, s)
+	return s
+}
+
+// AddSequences adds sequences to mask on all streams, including the streams that have already been added.
+// Bytes that are already in the buffer of a stream are not matched against the new sequences.
+func (m *Masker) AddSequences(sequences [][]byte) {
+	m.AddLabeledSequences("", sequences)
+}
+
+// AddLabeledSequences adds sequences in the same way as AddSequences does. The masks of the sequences are
+// reported with the given label in the report of the masker, e.g. to identify a secret without revealing its value.
+func (m *Masker) AddLabeledSequences(label string, sequences [][]byte) {
+	m.streamsLock.Lock()
+	defer m.streamsLock.Unlock()
+
+	m.sequences = append(m.sequences, labelSequences(label, sequences)...)
+	for _, s := range m.streams {
+		s.matcher.add(label, sequences...)
 	}
-	return &s
 }
 
 // Start continuously flushes the input buffer for each frame for which the buffer delay has passed.
@@ -3,66 +3,123 @@ package masker
 import (
 	"bytes"
 	"crypto/subtle"
+	"regexp"
+	"sync"
 )
 
+// regexWindowSize is the number of bytes that a regexDetector keeps to match its regular expression against.
+// Matches of regular expressions that are longer than this are not detected.
+const regexWindowSize = 4096
+
 // matches represents a set of sequence matches. The key is the index at which the match is found and the value is the
-// length of the match. The index corresponds to the index of the byte in the BufferedIndex of the stream.
-type matches map[int64]int
+// match. The index corresponds to the index of the byte in the BufferedIndex of the stream.
+type matches map[int64]match
+
+// match is a match of a sequence or pattern.
+type match struct {
+	length int
+	// label is the label of the matched sequence or pattern.
+	label string
+}
 
 // add a new match to the map if it does not yet exist or the existing match has a shorter length.
-func (m matches) add(index int64, length int) matches {
+func (m matches) add(index int64, length int, label string) matches {
 	existing, exists := m[index]
-	if !exists || existing < length {
-		m[index] = length
+	if !exists || existing.length < length {
+		m[index] = match{length: length, label: label}
 	}
 	return m
 }
 
-// matcher combines multiple sequenceMatchers to check for matches of secrets against any of them.
+// matcher combines multiple sequenceMatchers and regexDetectors to check for matches of secrets against any of them.
 type matcher struct {
-	detectors    []*sequenceDetector
-	currentIndex int64
+	detectors      []*sequenceDetector
+	regexDetectors []*regexDetector
+	encodings      []Encoding
+	currentIndex   int64
+	lock           sync.Mutex
 }
 
-// newMatcher returns a new matcher that contains a sequenceDetector for all given sequences.
-func newMatcher(sequences [][]byte) *matcher {
+// newMatcher returns a new matcher that contains a sequenceDetector for all given sequences
+// and for their encoded forms in each of the given encodings.
+func newMatcher(sequences []labeledSequence, encodings []Encoding) *matcher {
 	res := &matcher{
 		detectors: make([]*sequenceDetector, 0, len(sequences)),
+		encodings: encodings,
 	}
+	for _, sequence := range sequences {
+		res.add(sequence.label, sequence.value)
+	}
+	return res
+}
+
+// add adds a sequenceDetector for each of the given sequences and their encoded forms.
+// Matches of the sequences are reported with the given label.
+func (m *matcher) add(label string, sequences ...[]byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 
 	for _, sequence := range sequences {
-		res.detectors = append(res.detectors, &sequenceDetector{
-			sequence: sequence,
-			offset:   0,
-		})
-		// Add detectors for any repetitions in the sequence.
-		// For example, if the sequence is "aaab", we also require a detector for "aaaab" and "aaaaab".
-		for length, count := range sequenceRepetitions(sequence) {
-			for i := 1; i <= count; i++ {
-				prefixedSequence := make([]byte, len(sequence)+length*i)
-				copy(prefixedSequence, sequence[:length*i])
-				copy(prefixedSequence[length*i:], sequence)
-				res.detectors = append(res.detectors, &sequenceDetector{
-					sequence: prefixedSequence,
-					offset:   length * i,
-				})
-			}
+		m.addSequence(label, sequence)
+		for _, encoded := range encodedSequences(sequence, m.encodings) {
+			m.addSequence(label, encoded)
 		}
 	}
-	return res
+}
+
+// addSequence adds a sequenceDetector for the sequence.
+func (m *matcher) addSequence(label string, sequence []byte) {
+	m.detectors = append(m.detectors, &sequenceDetector{
+		sequence: sequence,
+		offset:   0,
+		label:    label,
+	})
+	// Add detectors for any repetitions in the sequence.
+	// For example, if the sequence is "aaab", we also require a detector for "aaaab" and "aaaaab".
+	for length, count := range sequenceRepetitions(sequence) {
+		for i := 1; i <= count; i++ {
+			prefixedSequence := make([]byte, len(sequence)+length*i)
+			copy(prefixedSequence, sequence[:length*i])
+			copy(prefixedSequence[length*i:], sequence)
+			m.detectors = append(m.detectors, &sequenceDetector{
+				sequence: prefixedSequence,
+				offset:   length * i,
+				label:    label,
+			})
+		}
+	}
+}
+
+// addPatterns adds a regexDetector for each of the given regular expressions.
+// Matches of a regular expression are reported with the expression as label.
+func (m *matcher) addPatterns(patterns ...*regexp.Regexp) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, pattern := range patterns {
+		m.regexDetectors = append(m.regexDetectors, newRegexDetector(pattern, regexWindowSize))
+	}
 }
 
 // write takes in a slice of bytes and returns all matches found by any of its detectors.
 func (m *matcher) write(in []byte) matches {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
 	res := matches{}
 	for i, b := range in {
 		for _, detector := range m.detectors {
 			match := detector.writeByte(b)
 			if match {
-				res = res.add(m.currentIndex+int64(i-detector.length()+1), detector.length())
+				res = res.add(m.currentIndex+int64(i-detector.length()+1), detector.length(), detector.label)
 			}
 		}
 	}
+	for _, detector := range m.regexDetectors {
+		for index, match := range detector.write(m.currentIndex, in) {
+			res = res.add(index, match.length, match.label)
+		}
+	}
 	m.currentIndex += int64(len(in))
 	return res
 }
@@ -72,6 +129,7 @@ type sequenceDetector struct {
 	sequence []byte
 	offset   int
 	index    int
+	label    string
 }
 
 // length returns the length of the sequence corrected for its offset.
@@ -115,6 +173,82 @@ func (d *sequenceDetector) writeByte(in byte) bool {
 	return sequenceComplete == 1
 }
 
+// regexDetector detects matches of a regular expression in the bytes it receives.
+// Regular expressions cannot be matched byte by byte like sequences, so the detector keeps a window
+// with the last bytes it received and matches the regular expression against the window whenever
+// new bytes are written. A match is reported when its last byte is written, which is the same moment
+// a sequenceDetector reports a match, so the match is found before the bytes are flushed.
+//
+// The window only keeps the tail of the received bytes that can still be part of a match that ends in
+// bytes written later: no more bytes than the maximum length of a match and no bytes before a byte that
+// cannot be part of a match. So small writes only cause the few bytes that can still match to be scanned again.
+type regexDetector struct {
+	regex      *regexp.Regexp
+	windowSize int
+	// maxLength is the maximum length of a match that is kept in the window.
+	maxLength  int
+	matchBytes [256]bool
+	window     []byte
+	// windowIndex is the index of the first byte of the window.
+	windowIndex int64
+}
+
+// newRegexDetector returns a detector for the regular expression that detects matches
+// with a length of at most the window size.
+func newRegexDetector(regex *regexp.Regexp, windowSize int) *regexDetector {
+	properties := analyzeRegex(regex)
+	maxLength := properties.maxLength
+	if maxLength == -1 || maxLength > windowSize {
+		maxLength = windowSize
+	}
+	return &regexDetector{
+		regex:      regex,
+		windowSize: windowSize,
+		maxLength:  maxLength,
+		matchBytes: properties.matchBytes,
+	}
+}
+
+// write takes in the bytes starting at the given index and returns the matches that end in these bytes.
+// Matches that end in bytes written before have already been returned by a previous call.
+// A match that is extended by the new bytes is returned again with its new length.
+func (d *regexDetector) write(index int64, in []byte) matches {
+	if len(d.window) == 0 {
+		d.windowIndex = index
+	}
+	previousLength := len(d.window)
+	d.window = append(d.window, in...)
+
+	res := matches{}
+	for _, loc := range d.regex.FindAllIndex(d.window, -1) {
+		if loc[1] > previousLength && loc[1] > loc[0] {
+			res = res.add(d.windowIndex+int64(loc[0]), loc[1]-loc[0], d.regex.String())
+		}
+	}
+
+	d.trim(previousLength)
+	return res
+}
+
+// trim drops the bytes from the window that cannot be part of a match that ends in bytes written later.
+// The byte preceding the remaining bytes is kept, so that assertions such as \b are evaluated with the byte preceding a match.
+// The bytes written before were already trimmed, so only the new bytes are checked for bytes that cannot be part of a match.
+func (d *regexDetector) trim(previousLength int) {
+	start := 0
+	for i := len(d.window) - 1; i >= previousLength; i-- {
+		if !d.matchBytes[d.window[i]] {
+			start = i
+			break
+		}
+	}
+	if len(d.window)-start > d.maxLength {
+		start = len(d.window) - d.maxLength
+	}
+
+	d.window = append(d.window[:0], d.window[start:]...)
+	d.windowIndex += int64(start)
+}
+
 // sequenceRepetitions finds all repetitions of bytes in the start of the sequence and returns a map of the length of
 // repeated sequences as the key and the number of repetitions as the value.
 // Example, if the input is aabaabaab, the "a" is repeated 1 time and "aab" is repeated 2 times,
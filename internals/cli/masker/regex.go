@@ -0,0 +1,149 @@
+package masker
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+)
+
+// regexProperties are properties of a regular expression that limit which bytes a regexDetector
+// has to keep to detect matches that end in bytes that are written later.
+type regexProperties struct {
+	// maxLength is the maximum length in bytes of a match, or -1 when the length of matches is unbounded.
+	maxLength int
+	// matchBytes contains the bytes that can be part of a match.
+	matchBytes [256]bool
+}
+
+// analyzeRegex returns the properties of the regular expression. When the expression cannot be analyzed,
+// the returned properties allow matches of any length and with any bytes.
+func analyzeRegex(regex *regexp.Regexp) regexProperties {
+	res := regexProperties{maxLength: -1}
+
+	// Regular expressions are compiled with the Perl flags by the regexp package.
+	re, err := syntax.Parse(regex.String(), syntax.Perl)
+	if err != nil {
+		for b := range res.matchBytes {
+			res.matchBytes[b] = true
+		}
+		return res
+	}
+
+	res.maxLength = maxMatchLength(re)
+	addMatchBytes(re, &res.matchBytes)
+	return res
+}
+
+// maxMatchLength returns the maximum length in bytes of a match of the expression,
+// or -1 when the length of matches is unbounded.
+func maxMatchLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		length := 0
+		for _, r := range re.Rune {
+			runeLength := utf8.RuneLen(r)
+			if re.Flags&syntax.FoldCase != 0 {
+				// A case folding of a character can be longer, e.g. the Kelvin sign for k.
+				for fold := unicode.SimpleFold(r); fold != r; fold = unicode.SimpleFold(fold) {
+					if foldLength := utf8.RuneLen(fold); foldLength > runeLength {
+						runeLength = foldLength
+					}
+				}
+			}
+			length += runeLength
+		}
+		return length
+	case syntax.OpCharClass:
+		length := 0
+		for i := 1; i < len(re.Rune); i += 2 {
+			if runeLength := utf8.RuneLen(re.Rune[i]); runeLength > length {
+				length = runeLength
+			}
+		}
+		return length
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return utf8.UTFMax
+	case syntax.OpCapture, syntax.OpQuest:
+		return maxMatchLength(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		length := maxMatchLength(re.Sub[0])
+		if length == 0 {
+			return 0
+		}
+		if length == -1 || re.Op != syntax.OpRepeat || re.Max == -1 {
+			return -1
+		}
+		return length * re.Max
+	case syntax.OpConcat:
+		length := 0
+		for _, sub := range re.Sub {
+			subLength := maxMatchLength(sub)
+			if subLength == -1 {
+				return -1
+			}
+			length += subLength
+		}
+		return length
+	case syntax.OpAlternate:
+		length := 0
+		for _, sub := range re.Sub {
+			subLength := maxMatchLength(sub)
+			if subLength == -1 {
+				return -1
+			}
+			if subLength > length {
+				length = subLength
+			}
+		}
+		return length
+	}
+	// Empty matches and assertions, such as ^ and \b, do not match any bytes.
+	return 0
+}
+
+// addMatchBytes marks the bytes that can be part of a match of the expression.
+// All bytes of multi-byte UTF-8 characters are marked when the expression can match any non-ASCII character.
+func addMatchBytes(re *syntax.Regexp, matchBytes *[256]bool) {
+	addRune := func(r rune) {
+		if r < utf8.RuneSelf {
+			matchBytes[r] = true
+			return
+		}
+		for b := utf8.RuneSelf; b < len(matchBytes); b++ {
+			matchBytes[b] = true
+		}
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			addRune(r)
+			if re.Flags&syntax.FoldCase != 0 {
+				for fold := unicode.SimpleFold(r); fold != r; fold = unicode.SimpleFold(fold) {
+					addRune(fold)
+				}
+			}
+		}
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			for r := lo; r <= hi && r < utf8.RuneSelf; r++ {
+				addRune(r)
+			}
+			if hi >= utf8.RuneSelf {
+				addRune(hi)
+			}
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		for b := range matchBytes {
+			if re.Op == syntax.OpAnyChar || b != '\n' {
+				matchBytes[b] = true
+			}
+		}
+	default:
+		for _, sub := range re.Sub {
+			addMatchBytes(sub, matchBytes)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package masker
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestAnalyzeRegex(t *testing.T) {
+	cases := map[string]struct {
+		regex           string
+		maxLength       int
+		matchBytes      string
+		otherBytes      string
+		matchesNonASCII bool
+	}{
+		"literal": {
+			regex:      `secret`,
+			maxLength:  6,
+			matchBytes: "secrt",
+			otherBytes: "SECRT -\n",
+		},
+		"bounded repetition": {
+			regex:      `AKIA[0-9A-Z]{16}`,
+			maxLength:  20,
+			matchBytes: "AKIZ09",
+			otherBytes: "akiz -\n",
+		},
+		"unbounded repetition": {
+			regex:      `eyJ[A-Za-z0-9_-]+`,
+			maxLength:  -1,
+			matchBytes: "eyJaz09_-",
+			otherBytes: " .=\n",
+		},
+		"alternation": {
+			regex:      `token=(abc|defgh)?`,
+			maxLength:  11,
+			matchBytes: "token=abcdefgh",
+			otherBytes: "TOKEN ",
+		},
+		"case insensitive": {
+			regex:           `(?i)key`,
+			maxLength:       5,
+			matchBytes:      "keyKEY",
+			otherBytes:      "abc ",
+			matchesNonASCII: true, // The Kelvin sign is a case folding of k.
+		},
+		"any character": {
+			regex:           `a.b`,
+			maxLength:       6,
+			matchBytes:      "ab- ",
+			otherBytes:      "\n",
+			matchesNonASCII: true,
+		},
+		"assertions": {
+			regex:      `^\bid\b$`,
+			maxLength:  2,
+			matchBytes: "id",
+			otherBytes: "^$ \n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			properties := analyzeRegex(regexp.MustCompile(tc.regex))
+
+			assert.Equal(t, properties.maxLength, tc.maxLength)
+			for _, b := range []byte(tc.matchBytes) {
+				assert.Equal(t, properties.matchBytes[b], true)
+			}
+			for _, b := range []byte(tc.otherBytes) {
+				assert.Equal(t, properties.matchBytes[b], false)
+			}
+			assert.Equal(t, properties.matchBytes[0xe2], tc.matchesNonASCII)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package masker
+
+import (
+	"sort"
+)
+
+// labeledSequence is a sequence to mask with the label by which its masks are reported.
+type labeledSequence struct {
+	label string
+	value []byte
+}
+
+// labelSequences labels all given sequences with the same label.
+func labelSequences(label string, sequences [][]byte) []labeledSequence {
+	res := make([]labeledSequence, len(sequences))
+	for i, sequence := range sequences {
+		res[i] = labeledSequence{
+			label: label,
+			value: sequence,
+		}
+	}
+	return res
+}
+
+// Mask reports how many times the sequences or pattern with a label were masked on a stream.
+type Mask struct {
+	// Label is the label of the masked sequences or the regular expression of the masked pattern.
+	Label string
+	// Stream is the name of the stream on which the masks were applied.
+	Stream string
+	Count  int
+}
+
+// Report returns how many times each label was masked on each stream, sorted by label and stream.
+// As the masks are applied when the buffer of a stream is flushed, the report should be retrieved after Stop() has returned.
+func (m *Masker) Report() []Mask {
+	m.streamsLock.Lock()
+	defer m.streamsLock.Unlock()
+
+	// Streams with the same name are reported together.
+	counts := map[Mask]int{}
+	for _, s := range m.streams {
+		s.matchesLock.Lock()
+		for label, count := range s.masked {
+			counts[Mask{Label: label, Stream: s.name}] += count
+		}
+		s.matchesLock.Unlock()
+	}
+
+	res := make([]Mask, 0, len(counts))
+	for mask, count := range counts {
+		mask.Count = count
+		res = append(res, mask)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Label != res[j].Label {
+			return res[i].Label < res[j].Label
+		}
+		return res[i].Stream < res[j].Stream
+	})
+	return res
+}
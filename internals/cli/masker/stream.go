@@ -10,6 +10,7 @@ import (
 
 // stream is a buffered io.Writer that masks all secrets written on it.
 type stream struct {
+	name          string
 	dest          io.Writer
 	buf           indexedBuffer
 	registerFrame func(*stream, time.Duration, int)
@@ -17,6 +18,11 @@ type stream struct {
 	matcher     *matcher
 	matches     matches
 	matchesLock sync.Mutex
+	// masked counts the number of masks applied to the stream per label.
+	masked map[string]int
+	// maskEnd is the index of the first byte after the last mask, or 0 if no mask has been applied yet.
+	// Matches that start before or directly at this index are part of the last mask.
+	maskEnd int64
 }
 
 // Write implements the io.Writer interface for the stream.
@@ -29,8 +35,8 @@ func (s *stream) Write(p []byte) (int, error) {
 
 	n, err := s.buf.write(p)
 
-	for index, length := range s.matcher.write(p[:n]) {
-		s.addMatch(index, length)
+	for index, match := range s.matcher.write(p[:n]) {
+		s.addMatch(index, match)
 	}
 
 	if n > 0 {
@@ -40,15 +46,15 @@ func (s *stream) Write(p []byte) (int, error) {
 	return n, err
 }
 
-// addMatch adds the match of a secret at the given index and with the given length to the map of matches.
+// addMatch adds the match of a secret at the given index to the map of matches.
 // If the associated bytes have already been written to the destination, the match is ignored to avoid storing matches
 // that are never being processed by flush().
-func (s *stream) addMatch(index int64, length int) {
+func (s *stream) addMatch(index int64, match match) {
 	s.matchesLock.Lock()
 	defer s.matchesLock.Unlock()
 
 	if index >= s.buf.currentIndex {
-		s.matches = s.matches.add(index, length)
+		s.matches = s.matches.add(index, match.length, match.label)
 	}
 }
 
@@ -60,19 +66,20 @@ func (s *stream) flush(n int) error {
 	// Increment the frameIndex before processing matches to avoid adding new matches in the processed frame.
 	for i := startIndex; i < endIndex; i++ {
 		s.matchesLock.Lock()
-		length, exists := s.matches[i]
+		match, exists := s.matches[i]
 		s.matchesLock.Unlock()
 
 		if exists {
 			// Get any unprocessed bytes before this match to the destination.
-			bytesBeforeMatch, err := s.buf.writeUpToIndex(s.dest, i)
+			_, err := s.buf.writeUpToIndex(s.dest, i)
 			if err != nil {
 				return err
 			}
 
-			// Only write the redaction text if there were bytes between this match and the previous match
-			// or this is the first flush for the buffer.
-			if bytesBeforeMatch > 0 || s.buf.currentIndex == 0 {
+			// Only write the redaction text if the match does not overlap or directly follow the previous mask,
+			// as the redaction text written for that mask already covers it.
+			if s.maskEnd == 0 || i > s.maskEnd {
+				s.countMask(match.label)
 				_, err = s.dest.Write([]byte("<redacted by SecretHub>"))
 				if err != nil {
 					return err
@@ -80,10 +87,14 @@ func (s *stream) flush(n int) error {
 			}
 
 			// Drop all bytes until the end of the mask.
-			_, err = s.buf.writeUpToIndex(ioutil.Discard, i+int64(length))
+			end := i + int64(match.length)
+			_, err = s.buf.writeUpToIndex(ioutil.Discard, end)
 			if err != nil {
 				return err
 			}
+			if end > s.maskEnd {
+				s.maskEnd = end
+			}
 
 			delete(s.matches, i)
 		}
@@ -98,6 +109,17 @@ func (s *stream) flush(n int) error {
 	return nil
 }
 
+// countMask counts a mask applied to the stream for the sequence or pattern with the given label.
+func (s *stream) countMask(label string) {
+	s.matchesLock.Lock()
+	defer s.matchesLock.Unlock()
+
+	if s.masked == nil {
+		s.masked = make(map[string]int)
+	}
+	s.masked[label]++
+}
+
 // indexedBuffer is a goroutine safe buffer that assigns every byte that is written to it with an incrementing index.
 type indexedBuffer struct {
 	buffer       bytes.Buffer
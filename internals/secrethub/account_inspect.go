@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
@@ -15,6 +12,7 @@ type AccountInspectCommand struct {
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
+	format        string
 }
 
 // NewAccountInspectCommand creates a new AccountInspectCommand.
@@ -30,6 +28,8 @@ func NewAccountInspectCommand(io ui.IO, newClient newClientFunc) *AccountInspect
 func (cmd *AccountInspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Show the details of your SecretHub account.")
 
+	registerInspectFormatFlag(clause).StringVar(&cmd.format)
+
 	command.BindAction(clause, cmd.Run)
 }
 
@@ -45,14 +45,7 @@ func (cmd *AccountInspectCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newOutputUser(user, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return printStructured(cmd.io.Output(), cmd.format, newOutputUser(user, cmd.timeFormatter))
 }
 
 // outputUser is a user friendly JSON representation of a user account.
@@ -1,9 +1,7 @@
 package secrethub
 
 import (
-	"fmt"
 	"sort"
-	"text/tabwriter"
 
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 
@@ -19,6 +17,7 @@ type ACLListCommand struct {
 	depth         int
 	ancestors     bool
 	useTimestamps bool
+	format        string
 	timeFormatter TimeFormatter
 	io            ui.IO
 	newClient     newClientFunc
@@ -40,6 +39,7 @@ func (cmd *ACLListCommand) Register(r command.Registerer) {
 	clause.Flag("depth", "The maximum depth to which the rules of child directories should be displayed. Defaults to -1 (no limit).").Short('d').Default("-1").IntVar(&cmd.depth)
 	clause.Flag("all", "List all rules that apply on the directory, including rules on parent directories.").Short('a').BoolVar(&cmd.ancestors)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFormatFlag(clause, accessRuleItem{}).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -52,7 +52,7 @@ func (cmd *ACLListCommand) Run() error {
 
 // beforeRun configures the command using the flag values.
 func (cmd *ACLListCommand) beforeRun() {
-	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format))
 }
 
 func (cmd *ACLListCommand) run() error {
@@ -103,27 +103,41 @@ func (cmd *ACLListCommand) run() error {
 
 	sort.Sort(api.SortDirPaths(paths))
 
-	tabWriter := tabwriter.NewWriter(cmd.io.Output(), 0, 4, 4, ' ', 0)
-	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", "PATH", "PERMISSIONS", "LAST EDITED", "ACCOUNT")
+	formatter, err := newListFormatter(cmd.format, cmd.io.Output(), 4, []string{"path", "permissions", "last edited", "account"})
+	if err != nil {
+		return err
+	}
 
 	for _, p := range paths {
 		rulesForPath := ruleMap[p]
 		sort.Sort(api.SortAccessRules(rules))
 
 		for _, rule := range rulesForPath {
-			fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n",
-				p,
-				rule.Permission,
-				cmd.timeFormatter.Format(rule.LastChangedAt.Local()),
-				rule.Account.Name,
-			)
+			item := accessRuleItem{
+				Path:       p.String(),
+				Permission: rule.Permission.String(),
+				LastEdited: cmd.timeFormatter.Format(rule.LastChangedAt.Local()),
+				Account:    rule.Account.Name.String(),
+			}
+			err = formatter.Write([]string{item.Path, item.Permission, item.LastEdited, item.Account}, item)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
-	err = tabWriter.Flush()
+	err = formatter.Flush()
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// accessRuleItem is the json and yaml format of an access rule listed by acl ls.
+type accessRuleItem struct {
+	Path       string `json:"Path"`
+	Permission string `json:"Permission"`
+	LastEdited string `json:"LastEdited"`
+	Account    string `json:"Account"`
+}
@@ -172,6 +172,7 @@ func (app *App) registerCommands() {
 	NewAccountCommand(app.io, app.clientFactory.NewClient, app.credentialStore).Register(app.cli)
 	NewCredentialCommand(app.io, app.clientFactory, app.credentialStore).Register(app.cli)
 	NewConfigCommand(app.io, app.credentialStore).Register(app.cli)
+	NewCacheCommand(app.io, app.credentialStore).Register(app.cli)
 	NewEnvCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 
 	// Commands
@@ -183,11 +184,15 @@ func (app *App) registerCommands() {
 	NewLsCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewMkDirCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewRmCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewCpCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewMvCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewTreeCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewDiffCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewInspectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewAuditCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewInjectCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewRunCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
+	NewMaskCommand(app.io, app.clientFactory.NewClient).Register(app.cli)
 	NewPrintEnvCommand(app.cli, app.io).Register(app.cli)
 
 	// Hidden commands
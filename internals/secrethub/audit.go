@@ -27,8 +27,6 @@ var (
 
 const (
 	defaultTerminalWidth = 80
-	formatTable          = "table"
-	formatJSON           = "json"
 	pipedOutputLineLimit = 1000
 )
 
@@ -0,0 +1,41 @@
+package secrethub
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// CacheClearCommand removes all cached secrets.
+type CacheClearCommand struct {
+	io              ui.IO
+	credentialStore CredentialConfig
+}
+
+// NewCacheClearCommand creates a new CacheClearCommand.
+func NewCacheClearCommand(io ui.IO, store CredentialConfig) *CacheClearCommand {
+	return &CacheClearCommand{
+		io:              io,
+		credentialStore: store,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CacheClearCommand) Register(r command.Registerer) {
+	clause := r.Command("clear", "Remove all cached secrets from the configuration directory.")
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run removes the cache directory.
+func (cmd *CacheClearCommand) Run() error {
+	err := os.RemoveAll(secretCacheDir(cmd.credentialStore.ConfigDir().Path()))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.io.Output(), "Cache cleared.")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/configdir"
@@ -32,10 +33,12 @@ func NewClientFactory(store CredentialConfig) ClientFactory {
 }
 
 type clientFactory struct {
-	client           *secrethub.Client
+	client           secrethub.ClientInterface
 	ServerURL        *url.URL
 	identityProvider string
 	proxyAddress     *url.URL
+	cacheMode        string
+	cacheTTL         time.Duration
 	store            CredentialConfig
 }
 
@@ -43,6 +46,8 @@ type clientFactory struct {
 func (f *clientFactory) Register(r FlagRegisterer) {
 	r.Flag("api-remote", "The SecretHub API address, don't set this unless you know what you're doing.").Hidden().URLVar(&f.ServerURL)
 	r.Flag("identity-provider", "Enable native authentication with a trusted identity provider. Options are `aws` (IAM + KMS), `gcp` (IAM + KMS) and `key`. When you run the CLI on one of the platforms, you can leverage their respective identity providers to do native keyless authentication. Defaults to key, which uses the default credential sourced from a file, command-line flag, or environment variable. ").Default("key").StringVar(&f.identityProvider)
+	r.Flag("cache", "Cache the secrets that are read in the configuration directory, encrypted with your credential, to keep working when the SecretHub API cannot be reached. Options are `off`, `prefer` (use a cached secret when it is not older than --cache-ttl and only read it from the API otherwise) and `fallback` (always read secrets from the API and only use a cached secret when the API cannot be reached). Only supported with the key identity provider.").Default(cacheModeOff).HintOptions(cacheModeOff, cacheModePrefer, cacheModeFallback).StringVar(&f.cacheMode)
+	r.Flag("cache-ttl", "The duration after which cached secrets expire and are no longer used, e.g. 1h or 30m.").Default(defaultCacheTTL.String()).DurationVar(&f.cacheTTL)
 	r.Flag("proxy-address", "Set to the address of a proxy to connect to the API through a proxy. The prepended scheme determines the proxy type (http, https and socks5 are supported). For example: `--proxy-address http://my-proxy:1234`").URLVar(&f.proxyAddress)
 }
 
@@ -50,7 +55,15 @@ func (f *clientFactory) Register(r FlagRegisterer) {
 // is set with the flag.
 func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
 	if f.client == nil {
+		switch f.cacheMode {
+		case "", cacheModeOff, cacheModePrefer, cacheModeFallback:
+		default:
+			return nil, ErrInvalidCacheMode(f.cacheMode)
+		}
+		useCache := f.cacheMode == cacheModePrefer || f.cacheMode == cacheModeFallback
+
 		var credentialProvider credentials.Provider
+		var cache *secretCache
 		switch strings.ToLower(f.identityProvider) {
 		case "aws":
 			credentialProvider = credentials.UseAWS()
@@ -58,10 +71,31 @@ func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
 			credentialProvider = credentials.UseGCPServiceAccount()
 		case "key":
 			credentialProvider = f.store.Provider()
+			if useCache {
+				key, err := f.store.Import()
+				if err == configdir.ErrCredentialNotFound {
+					return nil, ErrCredentialNotExist
+				} else if err != nil {
+					return nil, err
+				}
+
+				_, decrypter, err := key.Provide(nil)
+				if err != nil {
+					return nil, err
+				}
+
+				// Use the imported key for the client as well, so that the passphrase is only asked once.
+				credentialProvider = key
+				cache = newSecretCache(secretCacheDir(f.store.ConfigDir().Path()), f.cacheTTL, key.Encrypter(), decrypter)
+			}
 		default:
 			return nil, ErrUnknownIdentityProvider(f.identityProvider)
 		}
 
+		if useCache && cache == nil {
+			return nil, ErrCacheNotSupported(f.identityProvider)
+		}
+
 		options := f.baseClientOptions()
 		options = append(options, secrethub.WithCredentials(credentialProvider))
 
@@ -71,7 +105,12 @@ func (f *clientFactory) NewClient() (secrethub.ClientInterface, error) {
 		} else if err != nil {
 			return nil, err
 		}
-		f.client = client
+
+		if cache != nil {
+			f.client = newCachedClient(client, cache, f.cacheMode)
+		} else {
+			f.client = client
+		}
 	}
 	return f.client, nil
 }
@@ -0,0 +1,405 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kingpin"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrCannotCopyDir      = errMain.Code("cannot_copy_dir").Error("cannot copy directory. Use the -r flag to copy directories.")
+	ErrCopyIntoItself     = errMain.Code("copy_into_itself").ErrorPref("cannot copy %s into itself")
+	ErrInvalidCopyVersion = errMain.Code("invalid_copy_versions").ErrorPref("invalid value for --versions: %s. Options are all and latest")
+	ErrCopyToVersion      = errMain.Code("copy_to_version").Error("cannot copy to a secret version. Copying to a secret writes a new version of it")
+)
+
+const (
+	copyVersionsAll    = "all"
+	copyVersionsLatest = "latest"
+)
+
+// CpCommand copies secrets and directories.
+type CpCommand struct {
+	src       api.Path
+	dst       api.Path
+	recursive bool
+	versions  string
+	dryRun    bool
+	force     bool
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewCpCommand creates a new CpCommand.
+func NewCpCommand(io ui.IO, newClient newClientFunc) *CpCommand {
+	return &CpCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *CpCommand) Register(r command.Registerer) {
+	clause := r.Command("cp", "Copy a secret, secret version or directory, also to another repository.")
+	clause.Alias("copy")
+	clause.Arg("src-path", "The path to the secret, secret version or directory to copy (<namespace>/<repo>[/<path>][:<version>])").Required().SetValue(&cmd.src)
+	clause.Arg("dst-path", "The path to copy to. When it is an existing directory, the source is copied into it (<namespace>/<repo>[/<path>])").Required().SetValue(&cmd.dst)
+	clause.Flag("recursive", "Copy directories and their contents recursively.").Short('r').BoolVar(&cmd.recursive)
+	registerCopyVersionsFlag(clause).Default(copyVersionsLatest).StringVar(&cmd.versions)
+	registerDryRunFlag(clause).BoolVar(&cmd.dryRun)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run copies the secret, secret version or directory.
+func (cmd *CpCommand) Run() error {
+	allVersions, err := parseCopyVersions(cmd.versions)
+	if err != nil {
+		return err
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := planCopy(client, cmd.src, cmd.dst, cmd.recursive)
+	if err != nil {
+		return err
+	}
+
+	if cmd.dryRun {
+		plan.print(cmd.io.Output(), allVersions)
+		return nil
+	}
+
+	overwritten := plan.overwrittenSecrets()
+	if len(overwritten) > 0 {
+		ok, err := askRmConfirmation(
+			cmd.io,
+			fmt.Sprintf("This will write over %s: %s. "+
+				"Please type in the destination path to confirm", pluralize("existing secret", "existing secrets", len(overwritten)), strings.Join(overwritten, ", ")),
+			cmd.force,
+			plan.dst.String(),
+		)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	return plan.execute(client, cmd.io.Output(), allVersions)
+}
+
+func registerCopyVersionsFlag(r FlagRegisterer) *kingpin.FlagClause {
+	return r.Flag("versions", "Which versions of every secret to copy. Options are all and latest. When all versions are copied, they are written in order so that the version history is kept. When a secret version is given, only that version is copied. When a destination secret already exists, the copied versions are written on top of its versions, which requires confirmation or --force.").HintOptions(copyVersionsAll, copyVersionsLatest)
+}
+
+// parseCopyVersions returns whether the --versions flag is set to copy all versions.
+func parseCopyVersions(versions string) (bool, error) {
+	switch versions {
+	case copyVersionsAll:
+		return true, nil
+	case copyVersionsLatest:
+		return false, nil
+	default:
+		return false, ErrInvalidCopyVersion(versions)
+	}
+}
+
+// copyPlan contains the operations needed to copy a secret or directory.
+type copyPlan struct {
+	src   api.Path
+	dst   api.Path
+	isDir bool
+	// dirs are the directories to create, with parents before their children.
+	dirs    []api.DirPath
+	secrets []copiedSecret
+}
+
+// copiedSecret is a secret or secret version that is copied from src to dst.
+type copiedSecret struct {
+	src api.SecretPath
+	dst api.SecretPath
+	// dstExists is true when the destination secret already exists, in which case
+	// the copied versions are written on top of the versions of the existing secret.
+	dstExists bool
+}
+
+// planCopy determines which directories need to be created and which secrets need to be
+// copied to copy src to dst. When dst is an existing directory, src is copied into it.
+func planCopy(client secrethub.ClientInterface, src api.Path, dst api.Path, recursive bool) (*copyPlan, error) {
+	if !src.HasVersion() {
+		srcDir, err := src.ToDirPath()
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := client.Dirs().GetTree(srcDir.Value(), -1, false)
+		if err == nil {
+			if !recursive {
+				return nil, ErrCannotCopyDir
+			}
+			return planDirCopy(client, srcDir, dst, tree)
+		} else if !api.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+
+	srcSecret, err := src.ToSecretPath()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.Secrets().Get(unversionedSecretPath(srcSecret).Value())
+	if api.IsErrNotFound(err) {
+		return nil, ErrResourceNotFound(src)
+	} else if err != nil {
+		return nil, err
+	}
+
+	dstSecret, err := copyDestination(client, dst, srcSecret.GetSecret())
+	if err != nil {
+		return nil, err
+	}
+
+	if dstSecret.String() == unversionedSecretPath(srcSecret).Value() {
+		return nil, ErrCopyIntoItself(src)
+	}
+
+	secretPath, err := dstSecret.ToSecretPath()
+	if err != nil {
+		return nil, err
+	}
+
+	dstExists, err := client.Secrets().Exists(secretPath.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	return &copyPlan{
+		src:     src,
+		dst:     dstSecret,
+		secrets: []copiedSecret{{src: srcSecret, dst: secretPath, dstExists: dstExists}},
+	}, nil
+}
+
+// planDirCopy plans copying all directories and secrets in the tree of srcDir.
+func planDirCopy(client secrethub.ClientInterface, srcDir api.DirPath, dst api.Path, tree *api.Tree) (*copyPlan, error) {
+	target, err := copyDestination(client, dst, srcDir.GetDirName())
+	if err != nil {
+		return nil, err
+	}
+
+	dstDir, err := target.ToDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if dstDir.Value() == srcDir.Value() || strings.HasPrefix(dstDir.Value(), srcDir.Value()+"/") {
+		return nil, ErrCopyIntoItself(srcDir)
+	}
+
+	dstExists, err := client.Dirs().Exists(dstDir.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &copyPlan{
+		src:   api.Path(srcDir),
+		dst:   target,
+		isDir: true,
+	}
+
+	var walk func(dir *api.Dir, relPath string) error
+	walk = func(dir *api.Dir, relPath string) error {
+		dirPath := dstDir
+		if relPath != "" {
+			dirPath = api.DirPath(dstDir.Value() + "/" + relPath)
+		}
+
+		exists := false
+		if dstExists {
+			// When the destination already exists, the directories are merged.
+			exists, err = client.Dirs().Exists(dirPath.Value())
+			if err != nil {
+				return err
+			}
+		}
+		if !exists {
+			plan.dirs = append(plan.dirs, dirPath)
+		}
+
+		secrets := make([]*api.Secret, len(dir.Secrets))
+		copy(secrets, dir.Secrets)
+		sort.Slice(secrets, func(i, j int) bool {
+			return secrets[i].Name < secrets[j].Name
+		})
+		for _, secret := range secrets {
+			srcPath, err := tree.AbsSecretPath(secret.SecretID)
+			if err != nil {
+				return err
+			}
+
+			dstPath := dirPath.JoinSecret(secret.Name)
+			secretExists := false
+			if exists {
+				secretExists, err = client.Secrets().Exists(dstPath.Value())
+				if err != nil {
+					return err
+				}
+			}
+
+			plan.secrets = append(plan.secrets, copiedSecret{
+				src:       *srcPath,
+				dst:       dstPath,
+				dstExists: secretExists,
+			})
+		}
+
+		subDirs := make([]*api.Dir, len(dir.SubDirs))
+		copy(subDirs, dir.SubDirs)
+		sort.Slice(subDirs, func(i, j int) bool {
+			return subDirs[i].Name < subDirs[j].Name
+		})
+		for _, subDir := range subDirs {
+			subPath := subDir.Name
+			if relPath != "" {
+				subPath = relPath + "/" + subDir.Name
+			}
+
+			err := walk(subDir, subPath)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = walk(tree.RootDir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// copyDestination returns the path to copy the resource with the given name to.
+// When dst is an existing directory, the resource is copied into it.
+func copyDestination(client secrethub.ClientInterface, dst api.Path, name string) (api.Path, error) {
+	if dst.HasVersion() {
+		return "", ErrCopyToVersion
+	}
+
+	isDir, err := client.Dirs().Exists(dst.String())
+	if err != nil {
+		return "", err
+	}
+
+	if isDir {
+		return api.Path(dst.String() + "/" + name), nil
+	}
+	return dst, nil
+}
+
+// unversionedSecretPath returns the path of the secret without its version.
+func unversionedSecretPath(secretPath api.SecretPath) api.SecretPath {
+	return api.SecretPath(strings.SplitN(secretPath.Value(), ":", 2)[0])
+}
+
+// overwrittenSecrets returns the paths of the destination secrets that already exist.
+func (p *copyPlan) overwrittenSecrets() []string {
+	var res []string
+	for _, secret := range p.secrets {
+		if secret.dstExists {
+			res = append(res, secret.dst.String())
+		}
+	}
+	return res
+}
+
+// print prints the operations of the plan without performing them.
+func (p *copyPlan) print(w io.Writer, allVersions bool) {
+	for _, dir := range p.dirs {
+		fmt.Fprintf(w, "Create directory %s\n", dir)
+	}
+	for _, secret := range p.secrets {
+		fmt.Fprintln(w, secret.description(allVersions))
+	}
+}
+
+// execute creates the directories and copies the secrets of the plan, printing every operation.
+func (p *copyPlan) execute(client secrethub.ClientInterface, w io.Writer, allVersions bool) error {
+	for _, dir := range p.dirs {
+		_, err := client.Dirs().Create(dir.Value())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Create directory %s\n", dir)
+	}
+
+	for _, secret := range p.secrets {
+		err := secret.copy(client, allVersions)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, secret.description(allVersions))
+	}
+
+	return nil
+}
+
+// description returns a description of the copy operation.
+func (s copiedSecret) description(allVersions bool) string {
+	res := fmt.Sprintf("Copy %s to %s", s.src, s.dst)
+	if allVersions && !s.src.HasVersion() {
+		res = fmt.Sprintf("Copy all versions of %s to %s", s.src, s.dst)
+	}
+	if s.dstExists {
+		res += " (overwrites the existing secret)"
+	}
+	return res
+}
+
+// copy writes the secret version, the latest version or all versions of the secret to
+// the destination. When all versions are copied, they are written from oldest to newest.
+func (s copiedSecret) copy(client secrethub.ClientInterface, allVersions bool) error {
+	var versions []*api.SecretVersion
+	if allVersions && !s.src.HasVersion() {
+		var err error
+		versions, err = client.Secrets().Versions().ListWithData(s.src.Value())
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].Version < versions[j].Version
+		})
+	} else {
+		version, err := client.Secrets().Versions().GetWithData(s.src.Value())
+		if err != nil {
+			return err
+		}
+		versions = []*api.SecretVersion{version}
+	}
+
+	for _, version := range versions {
+		_, err := client.Secrets().Write(s.dst.Value(), version.Data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestCpCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		src       api.Path
+		dst       api.Path
+		recursive bool
+		versions  string
+		dryRun    bool
+		force     bool
+		promptIn  string
+		promptErr error
+		ops       []string
+		out       string
+		err       error
+	}{
+		"secret": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd/key",
+			versions: copyVersionsLatest,
+			ops:      []string{"write company/prd/key=dev-key"},
+			out:      "Copy company/dev/api_key to company/prd/key\n",
+		},
+		"secret version": {
+			src:      "company/dev/api_key:1",
+			dst:      "company/prd/key",
+			versions: copyVersionsAll,
+			ops:      []string{"write company/prd/key=old-key"},
+			out:      "Copy company/dev/api_key:1 to company/prd/key\n",
+		},
+		"secret into directory": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd",
+			versions: copyVersionsAll,
+			ops: []string{
+				"write company/prd/api_key=old-key",
+				"write company/prd/api_key=dev-key",
+			},
+			out: "Copy all versions of company/dev/api_key to company/prd/api_key\n",
+		},
+		"directory": {
+			src:       "company/dev/db",
+			dst:       "company/prd/database",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			ops: []string{
+				"mkdir company/prd/database",
+				"write company/prd/database/password=dev-password",
+				"write company/prd/database/user=admin",
+			},
+			out: "Create directory company/prd/database\n" +
+				"Copy company/dev/db/password to company/prd/database/password\n" +
+				"Copy company/dev/db/user to company/prd/database/user\n",
+		},
+		"repository into repository": {
+			src:       "company/dev",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsAll,
+			ops: []string{
+				"mkdir company/prd/dev",
+				"mkdir company/prd/dev/db",
+				"write company/prd/dev/api_key=old-key",
+				"write company/prd/dev/api_key=dev-key",
+				"write company/prd/dev/db/password=dev-password",
+				"write company/prd/dev/db/user=admin",
+			},
+			out: "Create directory company/prd/dev\n" +
+				"Create directory company/prd/dev/db\n" +
+				"Copy all versions of company/dev/api_key to company/prd/dev/api_key\n" +
+				"Copy all versions of company/dev/db/password to company/prd/dev/db/password\n" +
+				"Copy all versions of company/dev/db/user to company/prd/dev/db/user\n",
+		},
+		"directory is merged": {
+			src:       "company/dev/db",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			force:     true,
+			ops: []string{
+				"write company/prd/db/password=dev-password",
+				"write company/prd/db/user=admin",
+			},
+			out: "Copy company/dev/db/password to company/prd/db/password (overwrites the existing secret)\n" +
+				"Copy company/dev/db/user to company/prd/db/user\n",
+		},
+		"existing secret confirmed": {
+			src:      "company/dev/db/password",
+			dst:      "company/prd/db",
+			versions: copyVersionsAll,
+			promptIn: "company/prd/db/password\n",
+			ops: []string{
+				"write company/prd/db/password=dev-password",
+			},
+			out: "Copy all versions of company/dev/db/password to company/prd/db/password (overwrites the existing secret)\n",
+		},
+		"existing secret not confirmed": {
+			src:       "company/dev/db",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			promptIn:  "db\n",
+			out:       "Name does not match. Aborting.\n",
+		},
+		"existing secret cannot ask": {
+			src:       "company/dev/db",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			promptErr: ui.ErrCannotAsk,
+			err:       ErrCannotDoWithoutForce,
+		},
+		"dry run marks existing secrets": {
+			src:       "company/dev/db",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsAll,
+			dryRun:    true,
+			out: "Copy all versions of company/dev/db/password to company/prd/db/password (overwrites the existing secret)\n" +
+				"Copy all versions of company/dev/db/user to company/prd/db/user\n",
+		},
+		"dry run": {
+			src:       "company/dev",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsAll,
+			dryRun:    true,
+			out: "Create directory company/prd/dev\n" +
+				"Create directory company/prd/dev/db\n" +
+				"Copy all versions of company/dev/api_key to company/prd/dev/api_key\n" +
+				"Copy all versions of company/dev/db/password to company/prd/dev/db/password\n" +
+				"Copy all versions of company/dev/db/user to company/prd/dev/db/user\n",
+		},
+		"directory without recursive": {
+			src:      "company/dev/db",
+			dst:      "company/prd",
+			versions: copyVersionsLatest,
+			err:      ErrCannotCopyDir,
+		},
+		"directory into itself": {
+			src:       "company/dev",
+			dst:       "company/dev/db",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			err:       ErrCopyIntoItself(api.DirPath("company/dev")),
+		},
+		"secret onto itself": {
+			src:      "company/dev/api_key:1",
+			dst:      "company/dev",
+			versions: copyVersionsLatest,
+			err:      ErrCopyIntoItself(api.Path("company/dev/api_key:1")),
+		},
+		"to secret version": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd/api_key:1",
+			versions: copyVersionsLatest,
+			err:      ErrCopyToVersion,
+		},
+		"not found": {
+			src:      "company/dev/missing",
+			dst:      "company/prd",
+			versions: copyVersionsLatest,
+			err:      ErrResourceNotFound(api.Path("company/dev/missing")),
+		},
+		"invalid versions": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd",
+			versions: "some",
+			err:      ErrInvalidCopyVersion("some"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ops []string
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+			io.PromptErr = tc.promptErr
+			cmd := CpCommand{
+				src:       tc.src,
+				dst:       tc.dst,
+				recursive: tc.recursive,
+				versions:  tc.versions,
+				dryRun:    tc.dryRun,
+				force:     tc.force,
+				io:        io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return newCopyTestClient(&ops), nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, ops, tc.ops)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+// newCopyTestClient returns a client with the company/dev and company/prd repositories
+// that records all changes in ops.
+func newCopyTestClient(ops *[]string) fakeclient.Client {
+	dirs := map[string][]string{
+		"company/dev":    {"api_key", "db/password", "db/user"},
+		"company/dev/db": {"password", "user"},
+		"company/prd":    {"db/password"},
+		"company/prd/db": {"password"},
+	}
+	// The versions of every secret, from oldest to newest.
+	versions := map[string][]string{
+		"company/dev/api_key":     {"old-key", "dev-key"},
+		"company/dev/db/password": {"dev-password"},
+		"company/dev/db/user":     {"admin"},
+		"company/prd/db/password": {"prd-password"},
+	}
+
+	return fakeclient.Client{
+		DirService: &fakeclient.DirService{
+			GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+				secrets, ok := dirs[path]
+				if !ok {
+					return nil, api.ErrDirNotFound
+				}
+				return newTestTree(path, secrets), nil
+			},
+			ExistsFunc: func(path string) (bool, error) {
+				_, ok := dirs[path]
+				return ok, nil
+			},
+			CreateFunc: func(path string) (*api.Dir, error) {
+				*ops = append(*ops, "mkdir "+path)
+				return &api.Dir{}, nil
+			},
+			DeleteFunc: func(path string) error {
+				*ops = append(*ops, "rmdir "+path)
+				return nil
+			},
+		},
+		SecretService: &fakeclient.SecretService{
+			ExistsFunc: func(path string) (bool, error) {
+				_, ok := versions[path]
+				return ok, nil
+			},
+			GetFunc: func(path string) (*api.Secret, error) {
+				if _, ok := versions[path]; !ok {
+					return nil, api.ErrSecretNotFound
+				}
+				return &api.Secret{}, nil
+			},
+			WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				*ops = append(*ops, fmt.Sprintf("write %s=%s", path, data))
+				return &api.SecretVersion{}, nil
+			},
+			DeleteFunc: func(path string) error {
+				*ops = append(*ops, "rm "+path)
+				return nil
+			},
+			VersionService: &fakeclient.SecretVersionService{
+				ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+					// The versions are returned newest first, to check they are written in order.
+					var res []*api.SecretVersion
+					for i, data := range versions[path] {
+						res = append([]*api.SecretVersion{{Version: i + 1, Data: []byte(data)}}, res...)
+					}
+					return res, nil
+				},
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					elems := strings.SplitN(path, ":", 2)
+					secretVersions, ok := versions[elems[0]]
+					if !ok {
+						return nil, api.ErrSecretNotFound
+					}
+					version := len(secretVersions)
+					if len(elems) == 2 {
+						fmt.Sscan(elems[1], &version)
+					}
+					return &api.SecretVersion{Version: version, Data: []byte(secretVersions[version-1])}, nil
+				},
+			},
+		},
+	}
+}
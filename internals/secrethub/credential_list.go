@@ -1,10 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-	"strings"
-	"text/tabwriter"
-
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/iterator"
 
@@ -17,6 +13,7 @@ type CredentialListCommand struct {
 	io            ui.IO
 	newClient     newClientFunc
 	useTimestamps bool
+	format        string
 }
 
 // NewAccountInitCommand creates a new CredentialListCommand.
@@ -33,6 +30,7 @@ func (cmd *CredentialListCommand) Register(r command.Registerer) {
 	clause.Alias("list")
 
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFormatFlag(clause, credentialItem{}).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -44,15 +42,12 @@ func (cmd *CredentialListCommand) Run() error {
 		return err
 	}
 
-	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format))
 
-	w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
-	fmt.Fprintln(w,
-		"FINGERPRINT\t"+
-			"TYPE\t"+
-			"ENABLED\t"+
-			"CREATED\t"+
-			"DESCRIPTION")
+	formatter, err := newListFormatter(cmd.format, cmd.io.Output(), 2, []string{"fingerprint", "type", "enabled", "created", "description"})
+	if err != nil {
+		return err
+	}
 
 	it := client.Credentials().List(&secrethub.CredentialListParams{})
 	for {
@@ -68,20 +63,32 @@ func (cmd *CredentialListCommand) Run() error {
 			enabled = "yes"
 		}
 
-		row := []string{
-			cred.Fingerprint[:16],
-			string(cred.Type),
-			enabled,
-			timeFormatter.Format(cred.CreatedAt),
-			cred.Description,
+		item := credentialItem{
+			Fingerprint: cred.Fingerprint[:16],
+			Type:        string(cred.Type),
+			Enabled:     cred.Enabled,
+			Created:     timeFormatter.Format(cred.CreatedAt),
+			Description: cred.Description,
+		}
+		err = formatter.Write([]string{item.Fingerprint, item.Type, enabled, item.Created, item.Description}, item)
+		if err != nil {
+			return err
 		}
-		fmt.Fprintln(w, strings.Join(row, "\t"))
 	}
 
-	err = w.Flush()
+	err = formatter.Flush()
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// credentialItem is the json and yaml format of a credential listed by credential ls.
+type credentialItem struct {
+	Fingerprint string `json:"Fingerprint"`
+	Type        string `json:"Type"`
+	Enabled     bool   `json:"Enabled"`
+	Created     string `json:"Created"`
+	Description string `json:"Description"`
+}
@@ -0,0 +1,266 @@
+package secrethub
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrDiffDirAndSecret = errMain.Code("diff_dir_and_secret").ErrorPref("cannot compare the directory %s with the secret %s: both paths must be directories or secrets")
+)
+
+// DiffCommand compares the secrets in two directories, repositories or secret versions.
+type DiffCommand struct {
+	pathA      api.Path
+	pathB      api.Path
+	showValues bool
+	force      bool
+	io         ui.IO
+	newClient  newClientFunc
+}
+
+// NewDiffCommand creates a new DiffCommand.
+func NewDiffCommand(io ui.IO, newClient newClientFunc) *DiffCommand {
+	return &DiffCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *DiffCommand) Register(r command.Registerer) {
+	clause := r.Command("diff", "Show the secrets that were added, removed or changed between two directories, repositories or secret versions. Secret values are compared by their hashes and are not printed, unless --show-values is set.")
+	clause.Arg("path-a", "The directory, repository or secret to compare from "+optionalDirPathPlaceHolder+" or "+secretPathOptionalVersionPlaceHolder).Required().SetValue(&cmd.pathA)
+	clause.Arg("path-b", "The directory, repository or secret to compare to "+optionalDirPathPlaceHolder+" or "+secretPathOptionalVersionPlaceHolder).Required().SetValue(&cmd.pathB)
+	clause.Flag("show-values", "Print the values of the secrets that differ. You are asked for confirmation before any value is printed.").BoolVar(&cmd.showValues)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run prints the differences between the secrets at the two paths.
+func (cmd *DiffCommand) Run() error {
+	if cmd.showValues && !cmd.force {
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			"This prints the values of all secrets that differ in plaintext. Do you want to continue?",
+			ui.DefaultNo,
+		)
+		if err == ui.ErrCannotAsk {
+			return ErrCannotDoWithoutForce
+		} else if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	a, err := cmd.getDiffSecrets(client, cmd.pathA)
+	if err != nil {
+		return err
+	}
+
+	b, err := cmd.getDiffSecrets(client, cmd.pathB)
+	if err != nil {
+		return err
+	}
+
+	if a.isDir != b.isDir {
+		if a.isDir {
+			return ErrDiffDirAndSecret(cmd.pathA, cmd.pathB)
+		}
+		return ErrDiffDirAndSecret(cmd.pathB, cmd.pathA)
+	}
+
+	cmd.printDiff(cmd.io.Output(), diffSecrets(a.secrets, b.secrets))
+	return nil
+}
+
+// diffSide contains the secrets at one of the two compared paths.
+type diffSide struct {
+	isDir bool
+	// secrets contains the secrets by their path relative to the compared directory.
+	// When a secret is compared, it contains the secret with an empty path.
+	secrets map[string]diffSecret
+}
+
+// diffSecret is a secret version that is compared by the hash of its value.
+// The value itself is only kept when it is going to be printed.
+type diffSecret struct {
+	hash  [sha256.Size]byte
+	value []byte
+}
+
+// getDiffSecrets returns the latest version of every secret in the directory at the
+// given path or the version of the secret at the given path.
+func (cmd *DiffCommand) getDiffSecrets(client secrethub.ClientInterface, path api.Path) (diffSide, error) {
+	if !path.HasVersion() {
+		dirPath, err := path.ToDirPath()
+		if err != nil {
+			return diffSide{}, err
+		}
+
+		tree, err := client.Dirs().GetTree(dirPath.Value(), -1, false)
+		if err == nil {
+			secrets, err := cmd.getTreeSecrets(client, dirPath, tree)
+			if err != nil {
+				return diffSide{}, err
+			}
+			return diffSide{isDir: true, secrets: secrets}, nil
+		} else if !api.IsErrNotFound(err) {
+			return diffSide{}, err
+		}
+	}
+
+	secretPath, err := path.ToSecretPath()
+	if err != nil {
+		return diffSide{}, err
+	}
+
+	version, err := client.Secrets().Versions().GetWithData(secretPath.Value())
+	if api.IsErrNotFound(err) {
+		return diffSide{}, ErrResourceNotFound(path)
+	} else if err != nil {
+		return diffSide{}, err
+	}
+
+	return diffSide{
+		secrets: map[string]diffSecret{
+			"": cmd.newDiffSecret(version.Data),
+		},
+	}, nil
+}
+
+// getTreeSecrets returns the latest version of all secrets in the tree by their path relative to the given directory.
+func (cmd *DiffCommand) getTreeSecrets(client secrethub.ClientInterface, dirPath api.DirPath, tree *api.Tree) (map[string]diffSecret, error) {
+	secrets := make(map[string]diffSecret, len(tree.Secrets))
+	for _, secret := range tree.Secrets {
+		secretPath, err := tree.AbsSecretPath(secret.SecretID)
+		if err != nil {
+			return nil, err
+		}
+
+		versions, err := client.Secrets().Versions().ListWithData(secretPath.Value())
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		latest := versions[0]
+		for _, version := range versions[1:] {
+			if version.Version > latest.Version {
+				latest = version
+			}
+		}
+
+		relPath := strings.TrimPrefix(secretPath.Value(), dirPath.Value()+"/")
+		secrets[relPath] = cmd.newDiffSecret(latest.Data)
+	}
+	return secrets, nil
+}
+
+// newDiffSecret hashes the value and only keeps it when the values are printed.
+func (cmd *DiffCommand) newDiffSecret(value []byte) diffSecret {
+	secret := diffSecret{
+		hash: sha256.Sum256(value),
+	}
+	if cmd.showValues {
+		secret.value = value
+	}
+	return secret
+}
+
+const (
+	diffAdded   = "+"
+	diffRemoved = "-"
+	diffChanged = "~"
+)
+
+// diffEntry is a secret that differs between the two compared paths.
+type diffEntry struct {
+	path   string
+	change string
+	a      diffSecret
+	b      diffSecret
+}
+
+// diffSecrets returns the secrets that were added, removed or changed from a to b, sorted by path.
+func diffSecrets(a, b map[string]diffSecret) []diffEntry {
+	var entries []diffEntry
+	for path, secretA := range a {
+		secretB, ok := b[path]
+		if !ok {
+			entries = append(entries, diffEntry{path: path, change: diffRemoved, a: secretA})
+		} else if !bytes.Equal(secretA.hash[:], secretB.hash[:]) {
+			entries = append(entries, diffEntry{path: path, change: diffChanged, a: secretA, b: secretB})
+		}
+	}
+	for path, secretB := range b {
+		if _, ok := a[path]; !ok {
+			entries = append(entries, diffEntry{path: path, change: diffAdded, b: secretB})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+	return entries
+}
+
+// printDiff prints every entry with the kind of change, followed by a summary.
+func (cmd *DiffCommand) printDiff(w io.Writer, entries []diffEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No differences.")
+		return
+	}
+
+	counts := map[string]int{}
+	for _, entry := range entries {
+		counts[entry.change]++
+
+		path := entry.path
+		if path == "" {
+			path = fmt.Sprintf("%s -> %s", cmd.pathA, cmd.pathB)
+		}
+		fmt.Fprintf(w, "%s %s\n", entry.change, path)
+
+		if cmd.showValues {
+			if entry.change != diffAdded {
+				printDiffValue(w, diffRemoved, entry.a.value)
+			}
+			if entry.change != diffRemoved {
+				printDiffValue(w, diffAdded, entry.b.value)
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d added, %d removed, %d changed\n", counts[diffAdded], counts[diffRemoved], counts[diffChanged])
+}
+
+// printDiffValue prints every line of the value indented and prefixed with the change.
+func printDiffValue(w io.Writer, change string, value []byte) {
+	for _, line := range strings.Split(strings.TrimSuffix(string(value), "\n"), "\n") {
+		fmt.Fprintf(w, "    %s %s\n", change, line)
+	}
+}
@@ -0,0 +1,241 @@
+package secrethub
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/api/uuid"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestDiffCommand_Run(t *testing.T) {
+	dirs := map[string][]string{
+		"company/dev":      {"db/password", "db/user", "api_key"},
+		"company/prd":      {"db/password", "db/user", "token"},
+		"company/dev/db":   {"password", "user"},
+		"company/prd/db":   {"password", "user"},
+		"company/prd/same": {"api_key"},
+	}
+	versions := map[string][]string{
+		"company/dev/db/password":  {"dev-password"},
+		"company/dev/db/user":      {"admin"},
+		"company/dev/api_key":      {"old-key", "dev-key"},
+		"company/prd/db/password":  {"prd-password\nsecond line"},
+		"company/prd/db/user":      {"admin"},
+		"company/prd/token":        {"prd-token"},
+		"company/prd/same/api_key": {"old-key", "dev-key"},
+	}
+
+	cases := map[string]struct {
+		pathA      api.Path
+		pathB      api.Path
+		showValues bool
+		force      bool
+		promptIn   string
+		promptErr  error
+		out        string
+		err        error
+	}{
+		"repos": {
+			pathA: "company/dev",
+			pathB: "company/prd",
+			out: "- api_key\n" +
+				"~ db/password\n" +
+				"+ token\n" +
+				"\n1 added, 1 removed, 1 changed\n",
+		},
+		"directories": {
+			pathA: "company/dev/db",
+			pathB: "company/prd/db",
+			out: "~ password\n" +
+				"\n0 added, 0 removed, 1 changed\n",
+		},
+		"no differences": {
+			pathA: "company/dev",
+			pathB: "company/dev",
+			out:   "No differences.\n",
+		},
+		"directory and secret version": {
+			pathA: "company/prd/same",
+			pathB: "company/dev/api_key:2",
+			err:   ErrDiffDirAndSecret("company/prd/same", "company/dev/api_key:2"),
+		},
+		"secret versions": {
+			pathA: "company/dev/api_key:1",
+			pathB: "company/dev/api_key:2",
+			out: "~ company/dev/api_key:1 -> company/dev/api_key:2\n" +
+				"\n0 added, 0 removed, 1 changed\n",
+		},
+		"secret and latest version": {
+			pathA: "company/dev/api_key",
+			pathB: "company/dev/api_key:2",
+			out:   "No differences.\n",
+		},
+		"show values with force": {
+			pathA:      "company/dev",
+			pathB:      "company/prd",
+			showValues: true,
+			force:      true,
+			out: "- api_key\n" +
+				"    - dev-key\n" +
+				"~ db/password\n" +
+				"    - dev-password\n" +
+				"    + prd-password\n" +
+				"    + second line\n" +
+				"+ token\n" +
+				"    + prd-token\n" +
+				"\n1 added, 1 removed, 1 changed\n",
+		},
+		"show values confirmed": {
+			pathA:      "company/dev/api_key:1",
+			pathB:      "company/dev/api_key:2",
+			showValues: true,
+			promptIn:   "y\n",
+			out: "~ company/dev/api_key:1 -> company/dev/api_key:2\n" +
+				"    - old-key\n" +
+				"    + dev-key\n" +
+				"\n0 added, 0 removed, 1 changed\n",
+		},
+		"show values declined": {
+			pathA:      "company/dev",
+			pathB:      "company/prd",
+			showValues: true,
+			promptIn:   "n\n",
+			out:        "Aborting.\n",
+		},
+		"show values cannot ask": {
+			pathA:      "company/dev",
+			pathB:      "company/prd",
+			showValues: true,
+			promptErr:  ui.ErrCannotAsk,
+			err:        ErrCannotDoWithoutForce,
+		},
+		"directory and secret": {
+			pathA: "company/dev/api_key",
+			pathB: "company/dev",
+			err:   ErrDiffDirAndSecret("company/dev", "company/dev/api_key"),
+		},
+		"not found": {
+			pathA: "company/dev/missing",
+			pathB: "company/dev",
+			err:   ErrResourceNotFound(api.Path("company/dev/missing")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+			io.PromptErr = tc.promptErr
+
+			cmd := DiffCommand{
+				pathA:      tc.pathA,
+				pathB:      tc.pathB,
+				showValues: tc.showValues,
+				force:      tc.force,
+				io:         io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								secrets, ok := dirs[path]
+								if !ok {
+									return nil, api.ErrDirNotFound
+								}
+								return newTestTree(path, secrets), nil
+							},
+						},
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								ListWithDataFunc: func(path string) ([]*api.SecretVersion, error) {
+									var res []*api.SecretVersion
+									for i, data := range versions[path] {
+										res = append(res, &api.SecretVersion{Version: i + 1, Data: []byte(data)})
+									}
+									return res, nil
+								},
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									secretPath := api.SecretPath(path)
+									name := strings.SplitN(path, ":", 2)[0]
+									secretVersions, ok := versions[name]
+									if !ok {
+										return nil, api.ErrSecretNotFound
+									}
+									if !secretPath.HasVersion() {
+										return &api.SecretVersion{Data: []byte(secretVersions[len(secretVersions)-1])}, nil
+									}
+									version, err := secretPath.GetVersion()
+									assert.OK(t, err)
+									return &api.SecretVersion{Data: []byte(secretVersions[version[0]-'1'])}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+// newTestTree returns a tree of the directory at the given path that contains
+// secrets with the given paths relative to the directory.
+func newTestTree(path string, secretPaths []string) *api.Tree {
+	dirPath := api.DirPath(path)
+	parentPath, _ := dirPath.GetParentPath()
+	rootDir := &api.Dir{
+		DirID: uuid.New(),
+		Name:  dirPath.GetDirName(),
+	}
+	tree := &api.Tree{
+		ParentPath: parentPath,
+		RootDir:    rootDir,
+		Dirs:       map[uuid.UUID]*api.Dir{rootDir.DirID: rootDir},
+		Secrets:    map[uuid.UUID]*api.Secret{},
+	}
+
+	for _, secretPath := range secretPaths {
+		dir := rootDir
+		elems := strings.Split(secretPath, "/")
+		for _, name := range elems[:len(elems)-1] {
+			var subDir *api.Dir
+			for _, existing := range dir.SubDirs {
+				if existing.Name == name {
+					subDir = existing
+				}
+			}
+			if subDir == nil {
+				parentID := dir.DirID
+				subDir = &api.Dir{
+					DirID:    uuid.New(),
+					ParentID: &parentID,
+					Name:     name,
+				}
+				dir.SubDirs = append(dir.SubDirs, subDir)
+				tree.Dirs[subDir.DirID] = subDir
+			}
+			dir = subDir
+		}
+
+		secret := &api.Secret{
+			SecretID: uuid.New(),
+			DirID:    dir.DirID,
+			Name:     elems[len(elems)-1],
+		}
+		dir.Secrets = append(dir.Secrets, secret)
+		tree.Secrets[secret.SecretID] = secret
+	}
+
+	return tree
+}
@@ -0,0 +1,133 @@
+package secrethub
+
+import (
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+)
+
+// Errors
+var (
+	ErrEnvCheckFailed = errMain.Code("env_check_failed").ErrorPref("%s could not be resolved")
+)
+
+// EnvCheckCommand checks whether all secrets referenced in the environment exist and can be read.
+type EnvCheckCommand struct {
+	io          ui.IO
+	newClient   newClientFunc
+	environment *environment
+}
+
+// NewEnvCheckCommand creates a new EnvCheckCommand.
+func NewEnvCheckCommand(io ui.IO, newClient newClientFunc) *EnvCheckCommand {
+	return &EnvCheckCommand{
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvCheckCommand) Register(r command.Registerer) {
+	clause := r.Command("check", "Check that all secrets referenced in the environment exist and can be read, without printing their values.")
+	clause.HelpLong("The environment is sourced in the same way as the run command does. " +
+		"For every environment variable that is populated with secrets, the secrets it references are read and decrypted. " +
+		"The command fails when one or more variables cannot be resolved, which makes it suitable for use in CI pipelines.")
+
+	cmd.environment.register(clause)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run executes the command.
+func (cmd *EnvCheckCommand) Run() error {
+	env, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name, value := range env {
+		if value.containsSecret() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	sr := prefetchSecrets(newSecretReader(cmd.newClient), func(sr tpl.SecretReader) {
+		for _, name := range names {
+			// Errors are reported when the values are resolved with the prefetched secrets below.
+			_, _ = env[name].resolve(sr)
+		}
+	})
+
+	failed := 0
+	formatter := newColumnFormatter(cmd.io.Output(), 2, []string{"name", "path", "status"})
+	for _, name := range names {
+		recorder := &secretReadRecorder{secretReader: sr}
+		_, err := env[name].resolve(recorder)
+		if err != nil {
+			failed++
+		}
+
+		for _, read := range recorder.reads {
+			status := "ok"
+			if read.err != nil {
+				status = read.err.Error()
+			}
+			err := formatter.Write([]string{name, read.path, status})
+			if err != nil {
+				return err
+			}
+		}
+
+		// The value could not be resolved for another reason than reading a secret, e.g. a missing template variable.
+		if err != nil && !recorder.failed() {
+			err := formatter.Write([]string{name, "-", err.Error()})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = formatter.Flush()
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return ErrEnvCheckFailed(pluralize("environment variable", "environment variables", failed))
+	}
+	return nil
+}
+
+// secretReadRecorder is a tpl.SecretReader that records the path of every secret
+// that is read and the error that occurred reading it, if any.
+type secretReadRecorder struct {
+	secretReader tpl.SecretReader
+	reads        []secretReadResult
+}
+
+type secretReadResult struct {
+	path string
+	err  error
+}
+
+// ReadSecret reads the secret with the underlying reader and records the result.
+func (r *secretReadRecorder) ReadSecret(path string) (string, error) {
+	secret, err := r.secretReader.ReadSecret(path)
+	r.reads = append(r.reads, secretReadResult{path: path, err: err})
+	return secret, err
+}
+
+// failed returns whether reading one of the secrets failed.
+func (r *secretReadRecorder) failed() bool {
+	for _, read := range r.reads {
+		if read.err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package secrethub
+
+import (
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestEnvCheckCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		envar    map[string]string
+		expected string
+		err      error
+	}{
+		"all secrets readable": {
+			envar: map[string]string{
+				"DB_USER":     "company/app/db/user",
+				"DB_PASSWORD": "company/app/db/password",
+			},
+			expected: "NAME         PATH                     STATUS\n" +
+				"DB_PASSWORD  company/app/db/password  ok\n" +
+				"DB_USER      company/app/db/user      ok\n",
+		},
+		"missing secret": {
+			envar: map[string]string{
+				"DB_USER":  "company/app/db/user",
+				"API_KEY":  "company/app/missing",
+				"API_KEY2": "company/app/missing",
+			},
+			expected: "NAME      PATH                 STATUS\n" +
+				"API_KEY   company/app/missing  " + api.ErrSecretNotFound.Error() + "\n" +
+				"API_KEY2  company/app/missing  " + api.ErrSecretNotFound.Error() + "\n" +
+				"DB_USER   company/app/db/user  ok\n",
+			err: ErrEnvCheckFailed("2 environment variables"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := EnvCheckCommand{
+				io: io,
+				environment: &environment{
+					osStat: osStatFunc("secrethub.env", os.ErrNotExist),
+					envar:  tc.envar,
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if path == "company/app/missing" {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte("secret")}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.expected)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package secrethub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrUnsupportedShell = errMain.Code("unsupported_shell").ErrorPref("unsupported shell: %s. Options are bash, zsh, fish, powershell, dotenv and json")
+)
+
+const (
+	shellBash       = "bash"
+	shellZsh        = "zsh"
+	shellFish       = "fish"
+	shellPowerShell = "powershell"
+	shellDotEnv     = "dotenv"
+	shellJSON       = "json"
+)
+
+// EnvExportCommand prints the environment with all secrets sourced, in a format that can be loaded into a shell.
+type EnvExportCommand struct {
+	io                   ui.IO
+	newClient            newClientFunc
+	environment          *environment
+	osEnv                []string
+	shell                string
+	ignoreMissingSecrets bool
+}
+
+// NewEnvExportCommand creates a new EnvExportCommand.
+func NewEnvExportCommand(io ui.IO, newClient newClientFunc) *EnvExportCommand {
+	return &EnvExportCommand{
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+		osEnv:       os.Environ(),
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvExportCommand) Register(r command.Registerer) {
+	clause := r.Command("export", "Print the environment variables that are populated with secrets as statements for your shell, e.g. eval \"$(secrethub env export)\".")
+	clause.HelpLong("The environment is sourced in the same way as the run command does. Only the variables that differ from the current environment are printed. " +
+		"Note that the output contains the values of the secrets in plaintext.")
+	clause.Flag("shell", "The shell or format to print the statements for. Options are bash, zsh, fish, powershell, dotenv and json.").HintOptions(shellBash, shellZsh, shellFish, shellPowerShell, shellDotEnv, shellJSON).Default(shellBash).StringVar(&cmd.shell)
+	clause.Flag("ignore-missing-secrets", "Do not return an error when a secret does not exist and use an empty value instead.").BoolVar(&cmd.ignoreMissingSecrets)
+
+	cmd.environment.register(clause)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run executes the command.
+func (cmd *EnvExportCommand) Run() error {
+	format, err := newShellFormatter(cmd.shell)
+	if err != nil {
+		return err
+	}
+
+	envValues, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+
+	env, _, err := resolveEnv(envValues, newSecretReader(cmd.newClient), cmd.ignoreMissingSecrets)
+	if err != nil {
+		return err
+	}
+
+	// Variables that are not changed do not need to be exported.
+	osEnv, _ := parseKeyValueStringsToMap(cmd.osEnv)
+	for name, value := range env {
+		if osValue, ok := osEnv[name]; ok && osValue == value {
+			delete(env, name)
+		}
+	}
+
+	return format(cmd.io.Output(), env)
+}
+
+// shellFormatter writes the environment variables as statements for a shell.
+type shellFormatter func(w io.Writer, env map[string]string) error
+
+// newShellFormatter returns the formatter for the given shell.
+func newShellFormatter(shell string) (shellFormatter, error) {
+	switch shell {
+	case shellBash, shellZsh:
+		return statementFormatter(func(name, value string) string {
+			return fmt.Sprintf("export %s=%s", name, quotePOSIX(value))
+		}), nil
+	case shellFish:
+		return statementFormatter(func(name, value string) string {
+			return fmt.Sprintf("set -gx %s %s", name, quoteFish(value))
+		}), nil
+	case shellPowerShell:
+		return statementFormatter(func(name, value string) string {
+			return fmt.Sprintf("$Env:%s = %s", name, quotePowerShell(value))
+		}), nil
+	case shellDotEnv:
+		return statementFormatter(func(name, value string) string {
+			return fmt.Sprintf("%s=%s", name, quoteDotEnv(value))
+		}), nil
+	case shellJSON:
+		return func(w io.Writer, env map[string]string) error {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "    ")
+			return encoder.Encode(env)
+		}, nil
+	default:
+		return nil, ErrUnsupportedShell(shell)
+	}
+}
+
+// statementFormatter returns a shellFormatter that writes a line for every variable, sorted by name.
+func statementFormatter(statement func(name, value string) string) shellFormatter {
+	return func(w io.Writer, env map[string]string) error {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			_, err := fmt.Fprintln(w, statement(name, env[name]))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// quotePOSIX quotes the value in single quotes. Single quotes cannot be escaped
+// within single quotes, so they are closed, escaped and reopened.
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteFish quotes the value in single quotes, in which fish only interprets
+// escaped backslashes and single quotes.
+func quoteFish(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+// quotePowerShell quotes the value in single quotes, in which a single quote is escaped by doubling it.
+// PowerShell also accepts typographic quotes as single quotes, so these are doubled as well.
+func quotePowerShell(value string) string {
+	return "'" + strings.NewReplacer("'", "''", "\u2018", "\u2018\u2018", "\u2019", "\u2019\u2019", "\u201a", "\u201a\u201a", "\u201b", "\u201b\u201b").Replace(value) + "'"
+}
+
+// quoteDotEnv quotes the value in double quotes, escaping backslashes, double quotes and newlines.
+func quoteDotEnv(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+}
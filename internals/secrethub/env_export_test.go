@@ -0,0 +1,114 @@
+package secrethub
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestNewShellFormatter(t *testing.T) {
+	env := map[string]string{
+		"B": "it's a \"quote\" with $HOME and \\n",
+		"A": "multi\nline",
+	}
+
+	cases := map[string]struct {
+		expected string
+		err      error
+	}{
+		shellBash: {
+			expected: "export A='multi\nline'\n" +
+				"export B='it'\\''s a \"quote\" with $HOME and \\n'\n",
+		},
+		shellZsh: {
+			expected: "export A='multi\nline'\n" +
+				"export B='it'\\''s a \"quote\" with $HOME and \\n'\n",
+		},
+		shellFish: {
+			expected: "set -gx A 'multi\nline'\n" +
+				"set -gx B 'it\\'s a \"quote\" with $HOME and \\\\n'\n",
+		},
+		shellPowerShell: {
+			expected: "$Env:A = 'multi\nline'\n" +
+				"$Env:B = 'it''s a \"quote\" with $HOME and \\n'\n",
+		},
+		shellDotEnv: {
+			expected: "A=\"multi\\nline\"\n" +
+				"B=\"it's a \\\"quote\\\" with $HOME and \\\\n\"\n",
+		},
+		shellJSON: {
+			expected: "{\n" +
+				"    \"A\": \"multi\\nline\",\n" +
+				"    \"B\": \"it's a \\\"quote\\\" with $HOME and \\\\n\"\n" +
+				"}\n",
+		},
+		"cmd": {
+			err: ErrUnsupportedShell("cmd"),
+		},
+	}
+
+	for shell, tc := range cases {
+		t.Run(shell, func(t *testing.T) {
+			format, err := newShellFormatter(shell)
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			var buf bytes.Buffer
+			err = format(&buf, env)
+			assert.OK(t, err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestQuotePowerShell_TypographicQuotes(t *testing.T) {
+	assert.Equal(t, quotePowerShell("a\u2019b"), "'a\u2019\u2019b'")
+}
+
+func TestEnvExportCommand_Run(t *testing.T) {
+	io := fakeui.NewIO(t)
+	cmd := EnvExportCommand{
+		io: io,
+		environment: &environment{
+			osEnv:  []string{"UNCHANGED=value", "CHANGED=old"},
+			osStat: osStatFunc("secrethub.env", os.ErrNotExist),
+			envar: map[string]string{
+				"UNCHANGED": "company/repo/unchanged",
+				"CHANGED":   "company/repo/changed",
+				"NEW":       "company/repo/new",
+			},
+		},
+		osEnv: []string{"UNCHANGED=value", "CHANGED=old"},
+		shell: shellBash,
+		newClient: func() (secrethub.ClientInterface, error) {
+			return fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							secrets := map[string]string{
+								"company/repo/unchanged": "value",
+								"company/repo/changed":   "new",
+								"company/repo/new":       "secret",
+							}
+							return &api.SecretVersion{Data: []byte(secrets[path])}, nil
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "export CHANGED='new'\nexport NEW='secret'\n")
+}
@@ -0,0 +1,147 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrInvalidTemplateVarName = errMain.Code("invalid_template_var_name").ErrorPref("invalid template variable name %s: names can only contain letters, digits and underscores and cannot start with a digit")
+	ErrEmptyTemplateVarValue  = errMain.Code("empty_template_var_value").ErrorPref("the value of template variable %s is empty")
+	ErrDuplicateTemplateVar   = errMain.Code("duplicate_template_var").ErrorPref("template variables %s and %s have the same value %s")
+)
+
+// templateVarNamePattern matches the names that can be used as a variable in v2 templates.
+var templateVarNamePattern = regexp.MustCompile(`^[\pL_][\pL\d_]*$`)
+
+// envFileMode is the file mode of generated env files. They only contain
+// references to secrets, so they can be read by everyone.
+const envFileMode = 0644
+
+// EnvInitCommand generates a secrethub.env file that sources all secrets in a directory.
+type EnvInitCommand struct {
+	io        ui.IO
+	fromDir   api.DirPath
+	outFile   string
+	vars      map[string]string
+	force     bool
+	newClient newClientFunc
+}
+
+// NewEnvInitCommand creates a new EnvInitCommand.
+func NewEnvInitCommand(io ui.IO, newClient newClientFunc) *EnvInitCommand {
+	return &EnvInitCommand{
+		io:        io,
+		vars:      make(map[string]string),
+		newClient: newClient,
+	}
+}
+
+// Register adds a CommandClause and it's args and flags to a Registerer.
+func (cmd *EnvInitCommand) Register(r command.Registerer) {
+	clause := r.Command("init", "Generate a secrethub.env file that explicitly maps environment variables to all secrets in a directory.")
+	clause.HelpLong("The names of the environment variables are derived from the paths of the secrets in the same way as the --secrets-dir flag does: " +
+		"all `/`, '-' and '.' are replaced with `_` and the name is uppercased. Checking in the generated file makes the mapping explicit.")
+	clause.Flag("from-dir", "The directory containing the secrets to generate environment variables for.").Required().PlaceHolder(optionalDirPathPlaceHolder).SetValue(&cmd.fromDir)
+	clause.Flag("out-file", "The path of the file to write to.").Default(defaultEnvFile).StringVar(&cmd.outFile)
+	clause.Flag("var", "Factor out a template variable with `VAR=VALUE`. All parts of the secret paths that are equal to VALUE are replaced with ${VAR}, e.g. --var env=prod replaces company/prod/db with company/${env}/db.").Short('v').StringMapVar(&cmd.vars)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run generates the env file.
+func (cmd *EnvInitCommand) Run() error {
+	varNames := make(map[string]string, len(cmd.vars))
+	for name, value := range cmd.vars {
+		if !templateVarNamePattern.MatchString(name) {
+			return ErrInvalidTemplateVarName(name)
+		}
+		if value == "" {
+			return ErrEmptyTemplateVarValue(name)
+		}
+		if other, ok := varNames[value]; ok {
+			names := []string{name, other}
+			sort.Strings(names)
+			return ErrDuplicateTemplateVar(names[0], names[1], value)
+		}
+		varNames[value] = name
+	}
+
+	paths, err := newSecretsDirEnv(cmd.newClient, cmd.fromDir.Value()).paths()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(paths))
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s={{ %s }}\n", name, factorOutVars(paths[name], varNames))
+	}
+
+	_, err = os.Stat(cmd.outFile)
+	if err == nil && !cmd.force {
+		if cmd.io.IsOutputPiped() {
+			return ErrFileAlreadyExists
+		}
+
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf(
+				"File %s already exists, overwrite it?",
+				cmd.outFile,
+			),
+			ui.DefaultNo,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	err = ioutil.WriteFile(cmd.outFile, buf.Bytes(), envFileMode)
+	if err != nil {
+		return ErrCannotWrite(cmd.outFile, err)
+	}
+
+	absPath, err := filepath.Abs(cmd.outFile)
+	if err != nil {
+		return ErrCannotWrite(err)
+	}
+
+	fmt.Fprintf(cmd.io.Output(), "Written %s to %s\n", pluralize("environment variable", "environment variables", len(names)), absPath)
+	return nil
+}
+
+// factorOutVars replaces every element of the path that is equal to the value of a
+// template variable with that variable. The variables are given by their value.
+func factorOutVars(path string, varNames map[string]string) string {
+	elems := strings.Split(path, "/")
+	for i, elem := range elems {
+		if name, ok := varNames[elem]; ok {
+			elems[i] = "${" + name + "}"
+		}
+	}
+	return strings.Join(elems, "/")
+}
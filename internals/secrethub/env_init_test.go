@@ -0,0 +1,122 @@
+package secrethub
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestEnvInitCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		fromDir  api.DirPath
+		secrets  []string
+		vars     map[string]string
+		force    bool
+		existing string
+		expected string
+		err      error
+	}{
+		"secrets in directory": {
+			fromDir: "company/app/prod",
+			secrets: []string{"db/password", "db/user", "api-key", "tls.crt"},
+			expected: "API_KEY={{ company/app/prod/api-key }}\n" +
+				"DB_PASSWORD={{ company/app/prod/db/password }}\n" +
+				"DB_USER={{ company/app/prod/db/user }}\n" +
+				"TLS_CRT={{ company/app/prod/tls.crt }}\n",
+		},
+		"factor out variables": {
+			fromDir: "company/prod",
+			secrets: []string{"prod/db", "production"},
+			vars: map[string]string{
+				"env":  "prod",
+				"repo": "company",
+			},
+			expected: "PRODUCTION={{ ${repo}/${env}/production }}\n" +
+				"PROD_DB={{ ${repo}/${env}/${env}/db }}\n",
+		},
+		"name collision": {
+			fromDir:  "company/app",
+			secrets:  []string{"db/user", "db-user"},
+			expected: "",
+			err: errNameCollision{
+				name:  "DB_USER",
+				paths: [2]string{"company/app/db-user", "company/app/db/user"},
+			},
+		},
+		"existing file without force": {
+			fromDir:  "company/app",
+			secrets:  []string{"db"},
+			existing: "OLD=value\n",
+			expected: "OLD=value\n",
+			err:      ErrFileAlreadyExists,
+		},
+		"existing file with force": {
+			fromDir:  "company/app",
+			secrets:  []string{"db"},
+			existing: "OLD=value\n",
+			force:    true,
+			expected: "DB={{ company/app/db }}\n",
+		},
+		"invalid variable name": {
+			fromDir: "company/app",
+			vars: map[string]string{
+				"1env": "prod",
+			},
+			err: ErrInvalidTemplateVarName("1env"),
+		},
+		"duplicate variable value": {
+			fromDir: "company/app",
+			vars: map[string]string{
+				"env":   "prod",
+				"stage": "prod",
+			},
+			err: ErrDuplicateTemplateVar("env", "stage", "prod"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			outFile := filepath.Join(dir, "secrethub.env")
+			if tc.existing != "" {
+				writeTestFiles(t, dir, map[string]string{"secrethub.env": tc.existing})
+			}
+
+			io := fakeui.NewIO(t)
+			io.Out.Piped = true
+			cmd := EnvInitCommand{
+				io:      io,
+				fromDir: tc.fromDir,
+				outFile: outFile,
+				vars:    tc.vars,
+				force:   tc.force,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return newTestTree(path, tc.secrets), nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			if tc.expected != "" {
+				assert.Equal(t, readTestFiles(t, dir)["secrethub.env"], tc.expected)
+			} else {
+				assert.Equal(t, readTestFiles(t, dir), map[string]string{})
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package secrethub
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -11,6 +13,7 @@ import (
 type EnvListCommand struct {
 	io          ui.IO
 	environment *environment
+	verbose     bool
 }
 
 // NewEnvListCommand creates a new EnvListCommand.
@@ -23,9 +26,12 @@ func NewEnvListCommand(io ui.IO, newClient newClientFunc) *EnvListCommand {
 
 // Register adds a CommandClause and it's args and flags to a Registerer.
 func (cmd *EnvListCommand) Register(r command.Registerer) {
-	clause := r.Command("ls", "[BETA] List environment variable names that will be populated with secrets.")
-	clause.HelpLong("This command is hidden because it is still in beta. Future versions may break.")
+	clause := r.Command("ls", "List environment variable names that will be populated with secrets.")
+	clause.HelpLong("The environment is sourced in the same way as the run command does. " +
+		"With --verbose, the source of every variable is printed, together with the sources it shadows. " +
+		"Sources are listed in order of increasing precedence: the OS environment, the .secretsenv directory, --secrets-dir, the env files, secrethub:// references and --envar.")
 	clause.Alias("list")
+	clause.Flag("verbose", "Print the source of every environment variable and the sources it shadows.").BoolVar(&cmd.verbose)
 
 	cmd.environment.register(clause)
 
@@ -34,18 +40,49 @@ func (cmd *EnvListCommand) Register(r command.Registerer) {
 
 // Run executes the command.
 func (cmd *EnvListCommand) Run() error {
-	env, err := cmd.environment.env()
+	sourcedEnvs, err := cmd.environment.sourcedEnvs()
 	if err != nil {
 		return err
 	}
 
-	for key, value := range env {
-		// For now only environment variables in which a secret is loaded are printed.
-		// TODO: Make this behavior configurable.
+	// For every variable, keep track of the sources that define it, in order of increasing precedence.
+	sources := make(map[string][]string)
+	env := make(map[string]value)
+	for _, sourced := range sourcedEnvs {
+		for name, value := range sourced.env {
+			sources[name] = append(sources[name], sourced.source)
+			env[name] = value
+		}
+	}
+
+	// For now only environment variables in which a secret is loaded are printed.
+	var names []string
+	for name, value := range env {
 		if value.containsSecret() {
-			fmt.Fprintln(cmd.io.Output(), key)
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if !cmd.verbose {
+		for _, name := range names {
+			fmt.Fprintln(cmd.io.Output(), name)
 		}
+		return nil
 	}
 
-	return nil
+	formatter := newColumnFormatter(cmd.io.Output(), 2, []string{"name", "source", "shadows"})
+	for _, name := range names {
+		defined := sources[name]
+		shadowed := make([]string, len(defined)-1)
+		for i := range shadowed {
+			shadowed[i] = defined[len(defined)-2-i]
+		}
+
+		err = formatter.Write([]string{name, defined[len(defined)-1], strings.Join(shadowed, ", ")})
+		if err != nil {
+			return err
+		}
+	}
+	return formatter.Flush()
 }
@@ -0,0 +1,67 @@
+package secrethub
+
+import (
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEnvListCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		verbose  bool
+		expected string
+	}{
+		"names": {
+			expected: "DB_PASSWORD\nDB_USER\nTOKEN\n",
+		},
+		"verbose": {
+			verbose: true,
+			expected: "NAME         SOURCE                  SHADOWS\n" +
+				"DB_PASSWORD  --envar                 env file secrethub.env, OS environment\n" +
+				"DB_USER      env file secrethub.env  \n" +
+				"TOKEN        secrethub:// reference  OS environment\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := EnvListCommand{
+				io:      io,
+				verbose: tc.verbose,
+				environment: &environment{
+					osEnv:           []string{"DB_PASSWORD=plain", "TOKEN=secrethub://company/app/token", "HOME=/root"},
+					osStat:          osStatFunc("secrethub.env", nil),
+					readFile:        readFileFunc("secrethub.env", "DB_USER={{ company/app/db/user }}\nDB_PASSWORD={{ company/app/db/password }}\nDEBUG=true\n"),
+					templateVersion: "auto",
+					envar: map[string]string{
+						"DB_PASSWORD": "company/app/db/password",
+					},
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.OK(t, err)
+			assert.Equal(t, io.Out.String(), tc.expected)
+		})
+	}
+}
+
+func TestEnvListCommand_Run_NoDefaultEnvFile(t *testing.T) {
+	io := fakeui.NewIO(t)
+	cmd := EnvListCommand{
+		io: io,
+		environment: &environment{
+			osStat: osStatFunc("secrethub.env", os.ErrNotExist),
+		},
+	}
+
+	err := cmd.Run()
+
+	assert.OK(t, err)
+	assert.Equal(t, io.Out.String(), "")
+}
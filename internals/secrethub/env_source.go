@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"unicode"
 
@@ -39,12 +40,16 @@ type environment struct {
 	readFile                     func(filename string) ([]byte, error)
 	osStat                       func(filename string) (os.FileInfo, error)
 	envar                        map[string]string
-	envFile                      string
+	envFiles                     []string
 	templateVars                 map[string]string
 	templateVersion              string
 	dontPromptMissingTemplateVar bool
 	secretsDir                   string
 	secretsEnvDir                string
+	strictPrecedence             bool
+	// specFile is the secrets.yml spec of which specEnv contains the environment variables, if any.
+	specFile string
+	specEnv  map[string]string
 }
 
 func newEnvironment(io ui.IO, newClient newClientFunc) *environment {
@@ -61,22 +66,104 @@ func newEnvironment(io ui.IO, newClient newClientFunc) *environment {
 
 func (env *environment) register(clause *cli.CommandClause) {
 	clause.Flag("envar", "Source an environment variable from a secret at a given path with `NAME=<path>`").Short('e').StringMapVar(&env.envar)
-	clause.Flag("env-file", "The path to a file with environment variable mappings of the form `NAME=value`. Template syntax can be used to inject secrets.").StringVar(&env.envFile)
-	clause.Flag("template", "").Hidden().StringVar(&env.envFile)
+	clause.Flag("env-file", "The path to a file with environment variable mappings of the form `NAME=value`. Template syntax can be used to inject secrets. Other env files can be included with an `include <path>` line. Can be repeated, in which case later files override earlier ones.").StringsVar(&env.envFiles)
+	clause.Flag("template", "").Hidden().StringsVar(&env.envFiles)
 	clause.Flag("var", "Define the value for a template variable with `VAR=VALUE`, e.g. --var env=prod").Short('v').StringMapVar(&env.templateVars)
 	clause.Flag("template-version", "The template syntax version to be used. The options are v1, v2, latest or auto to automatically detect the version.").Default("auto").StringVar(&env.templateVersion)
 	clause.Flag("no-prompt", "Do not prompt when a template variable is missing and return an error instead.").BoolVar(&env.dontPromptMissingTemplateVar)
 	clause.Flag("secrets-dir", "Recursively load all secrets from a directory into environment variables. Names of the environment variables are derived from the path of the secret: all `/`, '-' and '.' are replaced with `_` and the name is uppercased.").StringVar(&env.secretsDir)
+	clause.Flag("strict-precedence", "Return an error when an environment variable is defined by more than one source, instead of using the definition with the highest precedence. Variables in the OS environment can always be overridden.").BoolVar(&env.strictPrecedence)
 	clause.Flag("env", "The name of the environment prepared by the set command (default is `default`)").Default("default").Hidden().StringVar(&env.secretsEnvDir)
 }
 
+// Names of the sources of environment variables, used to explain where a variable comes from.
+const (
+	envSourceOS        = "OS environment"
+	envSourceReference = "secrethub:// reference"
+	envSourceEnvar     = "--envar"
+)
+
+// includeDirective is the keyword with which an env file includes another env file.
+const includeDirective = "include"
+
+// sourcedEnv is the environment that is read from a single source.
+type sourcedEnv struct {
+	source string
+	env    map[string]value
+}
+
+// provenance describes where the value of an environment variable is defined.
+type provenance struct {
+	source string
+	file   string
+	line   int
+}
+
+// String returns the file and line on which the value is defined, e.g. secrethub.env:12.
+// If the value is not defined on a line of a file, the source is returned.
+func (p provenance) String() string {
+	if p.file != "" && p.line > 0 {
+		return fmt.Sprintf("%s:%d", p.file, p.line)
+	}
+	return p.source
+}
+
+// sourcedValue is a value that keeps track of the variable it is defined for and where
+// it is defined, so that errors resolving it can point to the definition.
+type sourcedValue struct {
+	value
+	name   string
+	origin provenance
+}
+
+func (v *sourcedValue) resolve(sr tpl.SecretReader) (string, error) {
+	res, err := v.value.resolve(sr)
+	if err != nil {
+		return "", ErrResolveEnvVar(v.name, v.origin, err)
+	}
+	return res, nil
+}
+
+// lineValue is a value that is defined on a specific line of a file.
+type lineValue interface {
+	value
+	lineNumber() int
+}
+
+// env returns the environment with all sources merged.
 func (env *environment) env() (map[string]value, error) {
+	sourcedEnvs, err := env.sourcedEnvs()
+	if err != nil {
+		return nil, err
+	}
+
+	if env.strictPrecedence {
+		err = checkPrecedence(sourcedEnvs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	envs := make([]map[string]value, len(sourcedEnvs))
+	for i, sourced := range sourcedEnvs {
+		envs[i] = sourced.env
+	}
+	return mergeEnvs(envs...), nil
+}
+
+// sourcedEnvs returns the environment of every source, in order of increasing precedence.
+func (env *environment) sourcedEnvs() ([]sourcedEnv, error) {
 	osEnvMap, _ := parseKeyValueStringsToMap(env.osEnv)
-	var sources []EnvSource
 
-	sources = append(sources, &osEnv{
+	type namedSource struct {
+		origin provenance
+		source EnvSource
+	}
+	var sources []namedSource
+
+	sources = append(sources, namedSource{provenance{source: envSourceOS}, &osEnv{
 		osEnv: osEnvMap,
-	})
+	}})
 
 	// .secretsenv dir (for backwards compatibility)
 	envDir := filepath.Join(secretspec.SecretEnvPath, env.secretsEnvDir)
@@ -86,74 +173,215 @@ func (env *environment) env() (map[string]value, error) {
 		if err != nil {
 			return nil, err
 		}
-		sources = append(sources, dirSource)
+		sources = append(sources, namedSource{provenance{source: "directory " + envDir}, dirSource})
+	}
+
+	// env consumables of a secrets.yml spec
+	if env.specEnv != nil {
+		sources = append(sources, namedSource{provenance{source: "spec " + env.specFile, file: env.specFile}, EnvFlags(env.specEnv)})
 	}
 
 	// --secrets-dir flag
 	if env.secretsDir != "" {
 		secretsDirEnv := newSecretsDirEnv(env.newClient, env.secretsDir)
-		sources = append(sources, secretsDirEnv)
+		sources = append(sources, namedSource{provenance{source: "--secrets-dir " + env.secretsDir}, secretsDirEnv})
 	}
 
-	//secrethub.env file
-	if env.envFile == "" {
+	// env files (secrethub.env by default)
+	envFiles := env.envFiles
+	if len(envFiles) == 0 {
 		_, err := env.osStat(defaultEnvFile)
 		if err == nil {
-			env.envFile = defaultEnvFile
+			envFiles = []string{defaultEnvFile}
 		} else if !os.IsNotExist(err) {
 			return nil, ErrReadDefaultEnvFile(defaultEnvFile, err)
 		}
 	}
 
-	if env.envFile != "" {
-		templateVariableReader, err := newVariableReader(osEnvMap, env.templateVars)
+	if len(envFiles) > 0 {
+		templateVariableReader, err := env.variableReader()
 		if err != nil {
 			return nil, err
 		}
 
-		if !env.dontPromptMissingTemplateVar {
-			templateVariableReader = newPromptMissingVariableReader(templateVariableReader, env.io)
-		}
+		for _, path := range envFiles {
+			files, err := env.readEnvFileWithIncludes(filepath.Clean(path), nil)
+			if err != nil {
+				return nil, err
+			}
 
-		raw, err := env.readFile(env.envFile)
-		if err != nil {
-			return nil, ErrCannotReadFile(env.envFile, err)
+			for _, file := range files {
+				parser, err := getTemplateParser(file.raw, env.templateVersion)
+				if err != nil {
+					return nil, err
+				}
+
+				envFile, err := ReadEnvFile(file.path, bytes.NewReader(file.raw), templateVariableReader, parser)
+				if err != nil {
+					return nil, err
+				}
+				sources = append(sources, namedSource{provenance{source: "env file " + file.path, file: file.path}, envFile})
+			}
 		}
+	}
+
+	// secret references (secrethub://)
+	referenceEnv := newReferenceEnv(osEnvMap)
+	sources = append(sources, namedSource{provenance{source: envSourceReference}, referenceEnv})
+
+	// --envar flag
+	// TODO: Validate the flags when parsing by implementing the Flag interface for EnvFlags.
+	flagEnv, err := NewEnvFlags(env.envar)
+	if err != nil {
+		return nil, err
+	}
+	sources = append(sources, namedSource{provenance{source: envSourceEnvar}, flagEnv})
 
-		parser, err := getTemplateParser(raw, env.templateVersion)
+	envs := make([]sourcedEnv, len(sources))
+	for i, source := range sources {
+		env, err := source.source.env()
 		if err != nil {
 			return nil, err
 		}
 
-		envFile, err := ReadEnvFile(env.envFile, bytes.NewReader(raw), templateVariableReader, parser)
-		if err != nil {
-			return nil, err
+		sourced := make(map[string]value, len(env))
+		for name, value := range env {
+			origin := source.origin
+			if v, ok := value.(lineValue); ok {
+				origin.line = v.lineNumber()
+			}
+			sourced[name] = &sourcedValue{
+				value:  value,
+				name:   name,
+				origin: origin,
+			}
 		}
-		sources = append(sources, envFile)
+		envs[i] = sourcedEnv{source: source.origin.source, env: sourced}
 	}
+	return envs, nil
+}
 
-	// secret references (secrethub://)
-	referenceEnv := newReferenceEnv(osEnvMap)
-	sources = append(sources, referenceEnv)
+// variableReader returns the reader for the template variables of the env files.
+func (env *environment) variableReader() (tpl.VariableReader, error) {
+	osEnvMap, _ := parseKeyValueStringsToMap(env.osEnv)
 
-	// --envar flag
-	// TODO: Validate the flags when parsing by implementing the Flag interface for EnvFlags.
-	flagEnv, err := NewEnvFlags(env.envar)
+	templateVariableReader, err := newVariableReader(osEnvMap, env.templateVars)
 	if err != nil {
 		return nil, err
 	}
-	sources = append(sources, flagEnv)
 
-	envs := make([]map[string]value, len(sources))
-	for _, source := range sources {
-		env, err := source.env()
+	if !env.dontPromptMissingTemplateVar {
+		return newPromptMissingVariableReader(templateVariableReader, env.io), nil
+	}
+	return templateVariableReader, nil
+}
+
+// envFileContent is the content of an env file from which the include directives are removed.
+type envFileContent struct {
+	path string
+	raw  []byte
+}
+
+// readEnvFileWithIncludes reads the env file on the given path and all files it includes, recursively.
+// Included files are returned before the file that includes them, so that the including file takes precedence.
+// The chain contains the files that include the file, which is used to detect include cycles.
+func (env *environment) readEnvFileWithIncludes(path string, chain []string) ([]envFileContent, error) {
+	for _, file := range chain {
+		if file == path {
+			return nil, ErrEnvFileIncludeCycle(strings.Join(append(chain, path), " -> "))
+		}
+	}
+
+	raw, err := env.readFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	includes, raw, err := parseIncludes(raw)
+	if err != nil {
+		return nil, ErrParsingTemplate(path, err)
+	}
+
+	// Copy the chain, so that files included next to each other do not share it.
+	chain = append(chain[:len(chain):len(chain)], path)
+
+	var files []envFileContent
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		included, err := env.readEnvFileWithIncludes(include, chain)
 		if err != nil {
 			return nil, err
 		}
-		envs = append(envs, env)
+		files = append(files, included...)
 	}
 
-	return mergeEnvs(envs...), nil
+	return append(files, envFileContent{path: path, raw: raw}), nil
+}
+
+// parseIncludes returns the paths of the files included with `include <path>` lines and the
+// content with these lines emptied, so that the line numbers of the other lines are preserved.
+func parseIncludes(raw []byte) ([]string, []byte, error) {
+	var includes []string
+	lines := bytes.Split(raw, []byte("\n"))
+	for i, line := range lines {
+		path, ok := parseIncludeDirective(string(line))
+		if !ok {
+			continue
+		}
+		if path == "" {
+			return nil, nil, ErrTemplate(i+1, errors.New("include directive does not specify a file"))
+		}
+
+		includes = append(includes, path)
+		lines[i] = nil
+	}
+	return includes, bytes.Join(lines, []byte("\n")), nil
+}
+
+// parseIncludeDirective returns the path of the file included on the line if the line is an include directive.
+// Lines containing an = sign are variable definitions, even if they start with include.
+func parseIncludeDirective(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed != includeDirective && !strings.HasPrefix(trimmed, includeDirective+" ") && !strings.HasPrefix(trimmed, includeDirective+"\t") {
+		return "", false
+	}
+	if strings.Contains(trimmed, "=") {
+		return "", false
+	}
+
+	path, _ := trimQuotes(strings.TrimSpace(strings.TrimPrefix(trimmed, includeDirective)))
+	return path, true
+}
+
+// checkPrecedence returns an error if a variable is defined by more than one source.
+// Variables in the OS environment are expected to be overridden, so they are not taken into account.
+func checkPrecedence(sourcedEnvs []sourcedEnv) error {
+	definitions := make(map[string][]*sourcedValue)
+	for _, sourced := range sourcedEnvs {
+		if sourced.source == envSourceOS {
+			continue
+		}
+		for name, value := range sourced.env {
+			definitions[name] = append(definitions[name], value.(*sourcedValue))
+		}
+	}
+
+	// The names are sorted to report conflicts in a deterministic order.
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if defined := definitions[name]; len(defined) > 1 {
+			return ErrConflictingEnvVar(name, defined[0].origin, defined[1].origin)
+		}
+	}
+	return nil
 }
 
 func mergeEnvs(envs ...map[string]value) map[string]value {
@@ -203,6 +431,21 @@ type secretsDirEnv struct {
 // The variable names are the relative paths of their corresponding secrets in uppercase snake case.
 // An error is returned if two secret paths map to the same variable name.
 func (s *secretsDirEnv) env() (map[string]value, error) {
+	paths, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]value, len(paths))
+	for name, path := range paths {
+		result[name] = newSecretValue(path)
+	}
+	return result, nil
+}
+
+// paths returns the paths of all secrets in the directory by the environment variable name they map to.
+// An error is returned if two secret paths map to the same variable name.
+func (s *secretsDirEnv) paths() (map[string]string, error) {
 	client, err := s.newClient()
 	if err != nil {
 		return nil, err
@@ -213,14 +456,19 @@ func (s *secretsDirEnv) env() (map[string]value, error) {
 		return nil, err
 	}
 
-	paths := make(map[string]string, tree.SecretCount())
+	secretPaths := make([]string, 0, tree.SecretCount())
 	for id := range tree.Secrets {
 		secretPath, err := tree.AbsSecretPath(id)
 		if err != nil {
 			return nil, err
 		}
-		path := secretPath.String()
+		secretPaths = append(secretPaths, secretPath.String())
+	}
+	// The paths are sorted to report collisions in a deterministic order.
+	sort.Strings(secretPaths)
 
+	paths := make(map[string]string, len(secretPaths))
+	for _, path := range secretPaths {
 		envVarName := s.envVarName(path)
 		if prevPath, found := paths[envVarName]; found {
 			return nil, errNameCollision{
@@ -233,12 +481,7 @@ func (s *secretsDirEnv) env() (map[string]value, error) {
 		}
 		paths[envVarName] = path
 	}
-
-	result := make(map[string]value, len(paths))
-	for name, path := range paths {
-		result[name] = newSecretValue(path)
-	}
-	return result, nil
+	return paths, nil
 }
 
 // envVarName returns the environment variable name corresponding to the secret on the specified path
@@ -370,25 +613,27 @@ func (dir EnvDir) env() (map[string]value, error) {
 
 type templateValue struct {
 	filepath  string
+	lineNo    int
 	template  tpl.Template
 	varReader tpl.VariableReader
 }
 
 func (v *templateValue) resolve(sr tpl.SecretReader) (string, error) {
-	value, err := v.template.Evaluate(v.varReader, sr)
-	if err != nil {
-		return "", ErrParsingTemplate(v.filepath, err)
-	}
-	return value, nil
+	return v.template.Evaluate(v.varReader, sr)
+}
+
+func (v *templateValue) lineNumber() int {
+	return v.lineNo
 }
 
 func (v *templateValue) containsSecret() bool {
 	return v.template.ContainsSecrets()
 }
 
-func newTemplateValue(filepath string, template tpl.Template, varReader tpl.VariableReader) value {
+func newTemplateValue(filepath string, lineNo int, template tpl.Template, varReader tpl.VariableReader) value {
 	return &templateValue{
 		filepath:  filepath,
+		lineNo:    lineNo,
 		template:  template,
 		varReader: varReader,
 	}
@@ -421,7 +666,7 @@ func (t envTemplate) env() (map[string]value, error) {
 			return nil, templateError(tpls.lineNo, err)
 		}
 
-		value := newTemplateValue(t.filepath, tpls.value, t.templateVarReader)
+		value := newTemplateValue(t.filepath, tpls.lineNo, tpls.value, t.templateVarReader)
 
 		result[key] = value
 	}
@@ -1,9 +1,13 @@
 package secrethub
 
 import (
+	"errors"
+	"os"
 	"sort"
 	"testing"
 
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl/fakes"
+
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/api/uuid"
 	"github.com/secrethub/secrethub-go/internals/assert"
@@ -152,3 +156,200 @@ func TestSecretsDirEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironment_env_StrictPrecedence(t *testing.T) {
+	cases := map[string]struct {
+		osEnv            []string
+		envFile          string
+		envar            map[string]string
+		strictPrecedence bool
+		err              error
+	}{
+		"reference shadows env file": {
+			osEnv:   []string{"DB_PASS=secrethub://company/app/db/pass"},
+			envFile: "DB_USER=admin\nDB_PASS={{ company/app/db/pass }}\n",
+			err: ErrConflictingEnvVar(
+				"DB_PASS",
+				provenance{source: "env file secrethub.env", file: "secrethub.env", line: 2},
+				provenance{source: envSourceReference},
+			),
+			strictPrecedence: true,
+		},
+		"envar shadows env file": {
+			envFile: "DB_PASS={{ company/app/db/pass }}\n",
+			envar: map[string]string{
+				"DB_PASS": "company/app/db/pass",
+			},
+			err: ErrConflictingEnvVar(
+				"DB_PASS",
+				provenance{source: "env file secrethub.env", file: "secrethub.env", line: 1},
+				provenance{source: envSourceEnvar},
+			),
+			strictPrecedence: true,
+		},
+		"os environment can be overridden": {
+			osEnv:            []string{"DB_PASS=plain"},
+			envFile:          "DB_PASS={{ company/app/db/pass }}\n",
+			strictPrecedence: true,
+		},
+		"conflicts are merged without strict precedence": {
+			osEnv:   []string{"DB_PASS=secrethub://company/app/db/pass"},
+			envFile: "DB_PASS={{ company/app/db/pass }}\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env := &environment{
+				osEnv:            tc.osEnv,
+				osStat:           osStatFunc("secrethub.env", nil),
+				readFile:         readFileFunc("secrethub.env", tc.envFile),
+				templateVersion:  "auto",
+				envar:            tc.envar,
+				strictPrecedence: tc.strictPrecedence,
+			}
+
+			_, err := env.env()
+
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestProvenance_String(t *testing.T) {
+	cases := map[string]struct {
+		provenance provenance
+		expected   string
+	}{
+		"line in file": {
+			provenance: provenance{source: "env file secrethub.env", file: "secrethub.env", line: 12},
+			expected:   "secrethub.env:12",
+		},
+		"file without line": {
+			provenance: provenance{source: "env file secrethub.env", file: "secrethub.env"},
+			expected:   "env file secrethub.env",
+		},
+		"flag": {
+			provenance: provenance{source: envSourceEnvar},
+			expected:   "--envar",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.provenance.String(), tc.expected)
+		})
+	}
+}
+
+func TestEnvironment_env_EnvFiles(t *testing.T) {
+	cases := map[string]struct {
+		envFiles []string
+		files    map[string]string
+		expected map[string]string
+		err      error
+	}{
+		"default env file": {
+			files: map[string]string{
+				"secrethub.env": "A=base\n",
+			},
+			expected: map[string]string{
+				"A": "base",
+			},
+		},
+		"later files override earlier files": {
+			envFiles: []string{"secrethub.env", "secrethub.prod.env"},
+			files: map[string]string{
+				"secrethub.env":      "A=base\nB=base\n",
+				"secrethub.prod.env": "B: prod\nC: prod\n",
+			},
+			expected: map[string]string{
+				"A": "base",
+				"B": "prod",
+				"C": "prod",
+			},
+		},
+		"including file overrides included file": {
+			envFiles: []string{"config/prod.env"},
+			files: map[string]string{
+				"config/prod.env":      "include base.env\nB=prod\n",
+				"config/base.env":      "include 'common/db.env'\nA=base\nB=base\n",
+				"config/common/db.env": "DB=common\nA=common\n",
+			},
+			expected: map[string]string{
+				"A":  "base",
+				"B":  "prod",
+				"DB": "common",
+			},
+		},
+		"variable named include": {
+			envFiles: []string{"secrethub.env"},
+			files: map[string]string{
+				"secrethub.env": "include = value\n",
+			},
+			expected: map[string]string{
+				"include": "value",
+			},
+		},
+		"include cycle": {
+			envFiles: []string{"a.env"},
+			files: map[string]string{
+				"a.env": "include b.env\n",
+				"b.env": "include c.env\n",
+				"c.env": "include a.env\n",
+			},
+			err: ErrEnvFileIncludeCycle("a.env -> b.env -> c.env -> a.env"),
+		},
+		"include itself": {
+			envFiles: []string{"a.env"},
+			files: map[string]string{
+				"a.env": "A=a\ninclude ./a.env\n",
+			},
+			err: ErrEnvFileIncludeCycle("a.env -> a.env"),
+		},
+		"included file does not exist": {
+			envFiles: []string{"a.env"},
+			files: map[string]string{
+				"a.env": "include b.env\n",
+			},
+			err: ErrCannotReadFile("b.env", os.ErrNotExist),
+		},
+		"include without file": {
+			envFiles: []string{"a.env"},
+			files: map[string]string{
+				"a.env": "A=a\ninclude\n",
+			},
+			err: ErrParsingTemplate("a.env", ErrTemplate(2, errors.New("include directive does not specify a file"))),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			env := &environment{
+				envFiles: tc.envFiles,
+				osStat:   osStatFunc("secrethub.env", nil),
+				readFile: func(filename string) ([]byte, error) {
+					content, ok := tc.files[filename]
+					if !ok {
+						return nil, os.ErrNotExist
+					}
+					return []byte(content), nil
+				},
+				templateVersion: "auto",
+			}
+
+			values, err := env.env()
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			actual := make(map[string]string, len(values))
+			for name, value := range values {
+				actual[name], err = value.resolve(fakes.FakeSecretReader{})
+				assert.OK(t, err)
+			}
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
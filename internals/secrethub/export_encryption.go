@@ -0,0 +1,95 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// Errors
+var (
+	ErrInvalidEncryptedExport     = errMain.Code("invalid_encrypted_export").ErrorPref("the export file is not a valid encrypted export: %s")
+	ErrUnsupportedExportAlgorithm = errMain.Code("unsupported_export_algorithm").ErrorPref("the export file is encrypted with an unsupported algorithm: %s")
+	ErrDecryptExport              = errMain.Code("decrypt_export_failed").Error("could not decrypt the export file: the passphrase is incorrect or the file is corrupted")
+)
+
+const (
+	// encryptedExportMagic is the first line of every encrypted export file.
+	encryptedExportMagic   = "SECRETHUB-ENCRYPTED-EXPORT"
+	encryptedExportVersion = 1
+	// encryptedExportAlgorithm is the name of the passphrase based key that export files are encrypted with.
+	encryptedExportAlgorithm = "scrypt"
+)
+
+// encryptedExport is the format of an export file that is encrypted with a passphrase.
+// The payload is a zip file encrypted with an scrypt derived AES-GCM key.
+type encryptedExport struct {
+	Version   int             `json:"version"`
+	Algorithm string          `json:"algorithm"`
+	Header    json.RawMessage `json:"header"`
+	Payload   []byte          `json:"payload"`
+}
+
+// encryptExport encrypts the contents of an export zip file with the given passphrase.
+func encryptExport(data []byte, passphrase []byte) ([]byte, error) {
+	key, err := credentials.NewPassBasedKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, header, err := key.Encrypt(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(encryptedExport{
+		Version:   encryptedExportVersion,
+		Algorithm: key.Name(),
+		Header:    rawHeader,
+		Payload:   payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(encryptedExportMagic+"\n"), encoded...), nil
+}
+
+// decryptExport decrypts an export file encrypted with encryptExport and
+// returns the contents of the zip file.
+func decryptExport(raw []byte, passphrase []byte) ([]byte, error) {
+	encoded := bytes.TrimPrefix(raw, []byte(encryptedExportMagic+"\n"))
+
+	export := encryptedExport{}
+	err := json.Unmarshal(encoded, &export)
+	if err != nil {
+		return nil, ErrInvalidEncryptedExport(err)
+	}
+
+	// The header is validated before the key is derived, because deriving the key is expensive.
+	if export.Version != encryptedExportVersion || export.Algorithm != encryptedExportAlgorithm {
+		return nil, ErrUnsupportedExportAlgorithm(export.Algorithm)
+	}
+
+	key, err := credentials.NewPassBasedKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := key.Decrypt(export.Payload, export.Header)
+	if err != nil {
+		return nil, ErrDecryptExport
+	}
+	return data, nil
+}
+
+// isEncryptedExport returns whether the given export file is encrypted.
+func isEncryptedExport(raw []byte) bool {
+	return bytes.HasPrefix(raw, []byte(encryptedExportMagic+"\n"))
+}
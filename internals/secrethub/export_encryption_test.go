@@ -0,0 +1,60 @@
+package secrethub
+
+import (
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEncryptExport(t *testing.T) {
+	data := []byte("zip file contents")
+
+	encrypted, err := encryptExport(data, []byte("passphrase"))
+	assert.OK(t, err)
+	assert.Equal(t, isEncryptedExport(encrypted), true)
+
+	t.Run("correct passphrase", func(t *testing.T) {
+		actual, err := decryptExport(encrypted, []byte("passphrase"))
+
+		assert.OK(t, err)
+		assert.Equal(t, actual, data)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		_, err := decryptExport(encrypted, []byte("wrong"))
+
+		assert.Equal(t, err, ErrDecryptExport)
+	})
+}
+
+func TestIsEncryptedExport(t *testing.T) {
+	cases := map[string]struct {
+		raw      []byte
+		expected bool
+	}{
+		"zip file": {
+			raw:      []byte("PK\x03\x04"),
+			expected: false,
+		},
+		"encrypted": {
+			raw:      []byte(encryptedExportMagic + "\n{}"),
+			expected: true,
+		},
+		"empty": {
+			raw:      []byte{},
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, isEncryptedExport(tc.raw), tc.expected)
+		})
+	}
+}
+
+func TestDecryptExport_Invalid(t *testing.T) {
+	_, err := decryptExport([]byte(encryptedExportMagic+"\n{\"version\":2,\"algorithm\":\"rot13\"}"), []byte("passphrase"))
+
+	assert.Equal(t, err, ErrUnsupportedExportAlgorithm("rot13"))
+}
@@ -17,3 +17,18 @@ func registerTimestampFlag(r FlagRegisterer) *kingpin.FlagClause {
 func registerForceFlag(r FlagRegisterer) *kingpin.FlagClause {
 	return r.Flag("force", "Ignore confirmation and fail instead of prompt for missing arguments.").Short('f')
 }
+
+func registerDryRunFlag(r FlagRegisterer) *cli.Flag {
+	return r.Flag("dry-run", "Print the operations that would be performed, without performing them.")
+}
+
+// registerOutputFormatFlag registers the --output-format flag of a list command, of which the help text
+// documents the fields of the given item, which is printed for every listed resource in the json and yaml formats.
+func registerOutputFormatFlag(r FlagRegisterer, item interface{}) *kingpin.FlagClause {
+	return r.Flag("output-format", "Specify the format in which to output the results. Options are: table, json and yaml. "+
+		"The json format prints an object per line and the yaml format prints a list of objects, with the fields "+describeListItem(item)+".").HintOptions(formatTable, formatJSON, formatYAML).Default(formatTable)
+}
+
+func registerInspectFormatFlag(r FlagRegisterer) *kingpin.FlagClause {
+	return r.Flag("output-format", "Specify the format in which to output the details. Options are: json and yaml.").HintOptions(formatJSON, formatYAML).Default(formatJSON)
+}
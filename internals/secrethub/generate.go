@@ -2,6 +2,7 @@ package secrethub
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -27,10 +28,27 @@ var (
 	ErrCouldNotFindCharSet       = errGenerate.Code("charset_not_found").ErrorPref("could not find charset: %s")
 	ErrMinFlagInvalidInteger     = errGenerate.Code("min_flag_invalid_int").ErrorPref("second part of --min flag is not an integer: %s")
 	ErrInvalidMinFlag            = errGenerate.Code("min_flag_invalid").ErrorPref("min flag must be of the form <charset name>:<minimum count>, invalid min flag: %s")
+	ErrInvalidPattern            = errGenerate.Code("invalid_pattern").ErrorPref("invalid pattern %s: %s")
+	ErrInvalidWordCount          = errGenerate.Code("invalid_word_count").Error("The number of words of a passphrase must be larger than 0")
+	ErrPassphraseAndPattern      = errGenerate.Code("passphrase_and_pattern").Error("--passphrase and --pattern cannot be used together")
+	ErrCharsetFlagsNotApplicable = errGenerate.Code("charset_flags_not_applicable").ErrorPref("--length, --charset, --min and --symbols cannot be used together with %s")
+	ErrExcludeSimilarPassphrase  = errGenerate.Code("exclude_similar_passphrase").Error("--exclude-similar cannot be used together with --passphrase")
+	ErrMinCharsetExcluded        = errGenerate.Code("min_charset_excluded").ErrorPref("--min %s cannot be used together with --exclude-similar, as all characters of the %s charset are excluded")
+	ErrMinCountsExceedLength     = errGenerate.Code("min_counts_exceed_length").ErrorPref("the --min flags require at least %d characters, which is more than the secret length of %d")
+	ErrEntropyTooLow             = errGenerate.Code("entropy_too_low").ErrorPref("the generated secret would have an entropy of %d bits, which is less than the %d bits required by --max-entropy-check")
 )
 
 const defaultLength = 22
 
+// similarCharacters are the characters that are excluded with --exclude-similar, because they are easily mistaken for each other.
+var similarCharacters = randchar.NewCharset("0O1lI")
+
+// entropyReporter is implemented by generators that can report the entropy of the secrets they generate.
+type entropyReporter interface {
+	// Entropy returns the number of bits of entropy of a generated secret of the given length.
+	Entropy(length int) float64
+}
+
 // GenerateSecretCommand generates a new secret and writes to the output path.
 type GenerateSecretCommand struct {
 	symbolsFlag         boolValue
@@ -46,6 +64,12 @@ type GenerateSecretCommand struct {
 	clearClipboardAfter time.Duration
 	clipper             clip.Clipper
 	newClient           newClientFunc
+	passphrase          bool
+	words               int
+	separator           string
+	pattern             string
+	excludeSimilar      bool
+	minEntropy          int
 }
 
 // NewGenerateSecretCommand creates a new GenerateSecretCommand.
@@ -65,8 +89,15 @@ func (cmd *GenerateSecretCommand) Register(r command.Registerer) {
 	clause.Flag("length", "The length of the generated secret. Defaults to "+strconv.Itoa(defaultLength)).PlaceHolder(strconv.Itoa(defaultLength)).Short('l').SetValue(&cmd.lengthFlag)
 	clause.Flag("min", "<charset>:<n> Ensure that the resulting password contains at least n characters from the given character set. Note that adding constraints reduces the strength of the secret. When possible, avoid any constraints.").SetValue(&cmd.mins)
 	clause.Flag("clip", "Copy the generated value to the clipboard. The clipboard is automatically cleared after "+units.HumanDuration(cmd.clearClipboardAfter)+".").Short('c').BoolVar(&cmd.copyToClipboard)
-	clause.Flag("charset", "Define the set of characters to randomly generate a password from. Options are all, alphanumeric, numeric, lowercase, uppercase, letters, symbols and human-readable. Multiple character sets can be combined by supplying them in a comma separated list. Defaults to alphanumeric.").Default("alphanumeric").HintOptions("all", "alphanumeric", "numeric", "lowercase", "uppercase", "letters", "symbols", "human-readable").SetValue(&cmd.charsetFlag)
+	clause.Flag("charset", "Define the set of characters to randomly generate a password from. Options are all, alphanumeric, numeric, lowercase, uppercase, letters, symbols and human-readable. Multiple character sets can be combined by supplying them in a comma separated list. Defaults to alphanumeric.").HintOptions("all", "alphanumeric", "numeric", "lowercase", "uppercase", "letters", "symbols", "human-readable").SetValue(&cmd.charsetFlag)
 	clause.Flag("symbols", "Include symbols in secret.").Short('s').Hidden().SetValue(&cmd.symbolsFlag)
+	clause.Flag("passphrase", "Generate a diceware passphrase of random words from EFF's large wordlist instead of a string of random characters.").BoolVar(&cmd.passphrase)
+	clause.Flag("words", "The number of words of a passphrase generated with --passphrase. Every word adds 12.9 bits of entropy.").Default(strconv.Itoa(defaultPassphraseWords)).IntVar(&cmd.words)
+	clause.Flag("separator", "The separator between the words of a passphrase generated with --passphrase.").Default(defaultPassphraseSeparator).StringVar(&cmd.separator)
+	clause.Flag("pattern", "Generate a secret that follows a pattern, e.g. XXXX-XXXX-9999. The placeholders 9 (digit), a (lowercase letter), A (uppercase letter), x (lowercase letter or digit), X (uppercase letter or digit), * (letter or digit) and # (symbol) "+
+		"are replaced with a random character. Other characters are kept as is. Use \\ to keep a placeholder character as is, e.g. \\X.").StringVar(&cmd.pattern)
+	clause.Flag("exclude-similar", "Exclude the characters 0, O, 1, l and I, which are easily mistaken for each other.").BoolVar(&cmd.excludeSimilar)
+	clause.Flag("max-entropy-check", "Refuse to generate a secret with an entropy lower than the given number of bits.").PlaceHolder("BITS").IntVar(&cmd.minEntropy)
 	clause.Arg("rand-command", "").Hidden().StringVar(&cmd.secondArg)
 	clause.Arg("length", "").Hidden().SetValue(&cmd.lengthArg)
 
@@ -75,17 +106,73 @@ func (cmd *GenerateSecretCommand) Register(r command.Registerer) {
 
 // before configures the command using the flag values.
 func (cmd *GenerateSecretCommand) before() error {
+	if cmd.passphrase && cmd.pattern != "" {
+		return ErrPassphraseAndPattern
+	}
+
+	if cmd.passphrase || cmd.pattern != "" {
+		if cmd.lengthFlag.IsSet() || cmd.lengthArg.IsSet() || cmd.charsetFlag.v.Size() > 0 || len(cmd.mins.v) > 0 || cmd.symbolsFlag.IsSet() {
+			if cmd.passphrase {
+				return ErrCharsetFlagsNotApplicable("--passphrase")
+			}
+			return ErrCharsetFlagsNotApplicable("--pattern")
+		}
+	}
+
+	var exclude randchar.Charset
+	if cmd.excludeSimilar {
+		exclude = similarCharacters
+	}
+
+	if cmd.passphrase {
+		if cmd.excludeSimilar {
+			return ErrExcludeSimilarPassphrase
+		}
+		if cmd.words <= 0 {
+			return ErrInvalidWordCount
+		}
+		cmd.generator = newPassphraseGenerator(cmd.words, cmd.separator)
+		return nil
+	}
+
+	if cmd.pattern != "" {
+		var err error
+		cmd.generator, err = newPatternGenerator(cmd.pattern, exclude)
+		return err
+	}
+
 	useSymbols, err := cmd.useSymbols()
 	if err != nil {
 		return err
 	}
 
 	charset := cmd.charsetFlag.v
+	if charset.Size() == 0 {
+		charset = randchar.Alphanumeric
+	}
 	if useSymbols {
 		charset = charset.Add(randchar.Symbols)
 	}
 
-	cmd.generator, err = randchar.NewRand(charset, cmd.mins.v...)
+	mins := cmd.mins.excluding(exclude)
+	length, err := cmd.length()
+	if err != nil {
+		return err
+	}
+
+	minCount := 0
+	for _, rule := range mins {
+		if rule.charset.Size() == 0 {
+			return ErrMinCharsetExcluded(rule.name+":"+strconv.Itoa(rule.count), rule.name)
+		}
+		minCount += rule.count
+	}
+	// An invalid length is reported by run().
+	if length > 0 && minCount > length {
+		return ErrMinCountsExceedLength(minCount, length)
+	}
+
+	cmd.generator, err = newCharsetGenerator(charset.Subtract(exclude), mins)
 	if err != nil {
 		return err
 	}
@@ -118,6 +205,15 @@ func (cmd *GenerateSecretCommand) run() error {
 		return ErrInvalidRandLength
 	}
 
+	entropyReporter, reportsEntropy := cmd.generator.(entropyReporter)
+	var entropy int
+	if reportsEntropy {
+		entropy = int(entropyReporter.Entropy(length))
+		if entropy < cmd.minEntropy {
+			return ErrEntropyTooLow(entropy, cmd.minEntropy)
+		}
+	}
+
 	data, err := cmd.generator.Generate(length)
 	if err != nil {
 		return err
@@ -134,6 +230,9 @@ func (cmd *GenerateSecretCommand) run() error {
 	}
 
 	fmt.Fprintf(cmd.io.Output(), "A randomly generated secret has been written to %s:%d.\n", path, version.Version)
+	if reportsEntropy {
+		fmt.Fprintf(cmd.io.Output(), "The secret has an entropy of %d bits.\n", entropy)
+	}
 
 	if cmd.copyToClipboard {
 		err = WriteClipboardAutoClear(data, cmd.clearClipboardAfter, cmd.clipper)
@@ -194,8 +293,65 @@ func (cmd *GenerateSecretCommand) useSymbols() (bool, error) {
 	return false, nil
 }
 
+// charsetGenerator generates secrets of random characters from a charset, with minimum counts of characters from other charsets.
+type charsetGenerator struct {
+	randchar.Generator
+	charset randchar.Charset
+	mins    []minRule
+}
+
+// newCharsetGenerator creates a generator of secrets of random characters from the charset that satisfy the minimum rules.
+func newCharsetGenerator(charset randchar.Charset, mins []minRule) (charsetGenerator, error) {
+	options := make([]randchar.Option, len(mins))
+	for i, rule := range mins {
+		options[i] = randchar.Min(rule.count, rule.charset)
+	}
+
+	generator, err := randchar.NewRand(charset, options...)
+	if err != nil {
+		return charsetGenerator{}, err
+	}
+
+	return charsetGenerator{
+		Generator: generator,
+		charset:   charset,
+		mins:      mins,
+	}, nil
+}
+
+// Entropy returns a lower bound of the number of bits of entropy of a generated secret of the given length.
+// The characters that satisfy a minimum rule are counted as if they are only picked from the charset of the rule.
+func (g charsetGenerator) Entropy(length int) float64 {
+	entropy := 0.0
+	for _, rule := range g.mins {
+		entropy += float64(rule.count) * math.Log2(float64(rule.charset.Size()))
+		length -= rule.count
+	}
+	return entropy + float64(length)*math.Log2(float64(g.charset.Size()))
+}
+
+// minRule is a minimum number of characters from a charset that a generated secret should contain.
+type minRule struct {
+	name    string
+	count   int
+	charset randchar.Charset
+}
+
 type minRuleValue struct {
-	v []randchar.Option
+	v []minRule
+}
+
+// excluding returns the rules with the characters in exclude removed from their charsets.
+func (ov *minRuleValue) excluding(exclude randchar.Charset) []minRule {
+	res := make([]minRule, len(ov.v))
+	for i, rule := range ov.v {
+		res[i] = minRule{
+			name:    rule.name,
+			count:   rule.count,
+			charset: rule.charset.Subtract(exclude),
+		}
+	}
+	return res
 }
 
 func (ov *minRuleValue) String() string {
@@ -218,7 +374,7 @@ func (ov *minRuleValue) Set(flagValue string) error {
 		return ErrCouldNotFindCharSet(elements[0])
 	}
 
-	ov.v = append(ov.v, randchar.Min(count, charset))
+	ov.v = append(ov.v, minRule{name: elements[0], count: count, charset: charset})
 	return nil
 }
 
@@ -0,0 +1,52 @@
+package secrethub
+
+import (
+	"crypto/rand"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	defaultPassphraseWords     = 6
+	defaultPassphraseSeparator = "-"
+)
+
+// passphraseGenerator generates diceware passphrases: a number of words picked at random from a wordlist.
+type passphraseGenerator struct {
+	wordlist  []string
+	words     int
+	separator string
+	reader    io.Reader
+}
+
+// newPassphraseGenerator creates a generator for passphrases of the given number of words
+// from EFF's large wordlist, joined by the separator.
+func newPassphraseGenerator(words int, separator string) passphraseGenerator {
+	return passphraseGenerator{
+		wordlist:  effLargeWordlist,
+		words:     words,
+		separator: separator,
+		reader:    rand.Reader,
+	}
+}
+
+// Generate returns a random passphrase. The length is ignored, as the passphrase
+// always consists of the configured number of words.
+func (g passphraseGenerator) Generate(int) ([]byte, error) {
+	words := make([]string, g.words)
+	for i := range words {
+		index, err := rand.Int(g.reader, big.NewInt(int64(len(g.wordlist))))
+		if err != nil {
+			return nil, err
+		}
+		words[i] = g.wordlist[index.Int64()]
+	}
+	return []byte(strings.Join(words, g.separator)), nil
+}
+
+// Entropy returns the number of bits of entropy of a generated passphrase.
+func (g passphraseGenerator) Entropy(int) float64 {
+	return float64(g.words) * math.Log2(float64(len(g.wordlist)))
+}
@@ -0,0 +1,66 @@
+package secrethub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestPassphraseGenerator(t *testing.T) {
+	cases := map[string]struct {
+		words     int
+		separator string
+		entropy   float64
+	}{
+		"default": {
+			words:     defaultPassphraseWords,
+			separator: defaultPassphraseSeparator,
+			entropy:   77.54887502163469,
+		},
+		"custom separator": {
+			words:     4,
+			separator: " ",
+			entropy:   51.69925001442312,
+		},
+	}
+
+	// Words of the wordlist that contain the separator cannot be told apart in the passphrase,
+	// so the passphrases are generated from a wordlist without separators.
+	wordlist := []string{"correct", "horse", "battery", "staple"}
+	isWord := make(map[string]bool, len(wordlist))
+	for _, word := range wordlist {
+		isWord[word] = true
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			generator := newPassphraseGenerator(tc.words, tc.separator)
+			generator.wordlist = wordlist
+
+			actual, err := generator.Generate(defaultLength)
+			assert.OK(t, err)
+
+			words := strings.Split(string(actual), tc.separator)
+			assert.Equal(t, len(words), tc.words)
+			for _, word := range words {
+				if !isWord[word] {
+					t.Errorf("generated word %s is not in the wordlist", word)
+				}
+			}
+			assert.Equal(t, newPassphraseGenerator(tc.words, tc.separator).Entropy(defaultLength), tc.entropy)
+		})
+	}
+}
+
+func TestEFFLargeWordlist(t *testing.T) {
+	assert.Equal(t, len(effLargeWordlist), 7776)
+
+	seen := make(map[string]bool, len(effLargeWordlist))
+	for _, word := range effLargeWordlist {
+		if seen[word] {
+			t.Errorf("duplicate word %s", word)
+		}
+		seen[word] = true
+	}
+}
@@ -0,0 +1,97 @@
+package secrethub
+
+import (
+	"math"
+
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+)
+
+// patternCharsets are the placeholders that can be used in a pattern with the characters they are replaced with.
+var patternCharsets = map[rune]randchar.Charset{
+	'9': randchar.Numeric,
+	'a': randchar.Lowercase,
+	'A': randchar.Uppercase,
+	'x': randchar.Lowercase.Add(randchar.Numeric),
+	'X': randchar.Uppercase.Add(randchar.Numeric),
+	'*': randchar.Alphanumeric,
+	'#': randchar.Symbols,
+}
+
+// patternEscape makes the character following it in a pattern a literal character instead of a placeholder.
+const patternEscape = '\\'
+
+// patternGenerator generates secrets that follow a pattern, e.g. XXXX-XXXX-9999 for license keys.
+type patternGenerator struct {
+	elements []patternElement
+}
+
+// patternElement is a single character of a pattern, which is either a literal
+// character or a placeholder that is replaced with a random character.
+type patternElement struct {
+	literal   []byte
+	generator randchar.Generator
+	size      int
+}
+
+// newPatternGenerator parses the pattern. Characters that are listed in exclude are never generated for placeholders.
+func newPatternGenerator(pattern string, exclude randchar.Charset) (patternGenerator, error) {
+	var elements []patternElement
+	placeholders := 0
+	escaped := false
+	for _, char := range pattern {
+		charset, isPlaceholder := patternCharsets[char]
+		switch {
+		case escaped || !isPlaceholder && char != patternEscape:
+			elements = append(elements, patternElement{literal: []byte(string(char))})
+			escaped = false
+		case char == patternEscape:
+			escaped = true
+		default:
+			charset = charset.Subtract(exclude)
+			generator, err := randchar.NewRand(charset)
+			if err != nil {
+				return patternGenerator{}, err
+			}
+			elements = append(elements, patternElement{generator: generator, size: charset.Size()})
+			placeholders++
+		}
+	}
+
+	if escaped {
+		return patternGenerator{}, ErrInvalidPattern(pattern, "it ends with an unescaped \\")
+	}
+	if placeholders == 0 {
+		return patternGenerator{}, ErrInvalidPattern(pattern, "it does not contain any placeholders")
+	}
+	return patternGenerator{elements: elements}, nil
+}
+
+// Generate returns a random secret that follows the pattern. The length is ignored,
+// as the length of the secret is determined by the pattern.
+func (g patternGenerator) Generate(int) ([]byte, error) {
+	var res []byte
+	for _, element := range g.elements {
+		if element.generator == nil {
+			res = append(res, element.literal...)
+			continue
+		}
+
+		char, err := element.generator.Generate(1)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, char...)
+	}
+	return res, nil
+}
+
+// Entropy returns the number of bits of entropy of a secret generated from the pattern.
+func (g patternGenerator) Entropy(int) float64 {
+	entropy := 0.0
+	for _, element := range g.elements {
+		if element.generator != nil {
+			entropy += math.Log2(float64(element.size))
+		}
+	}
+	return entropy
+}
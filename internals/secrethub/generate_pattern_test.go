@@ -0,0 +1,66 @@
+package secrethub
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
+)
+
+func TestPatternGenerator(t *testing.T) {
+	cases := map[string]struct {
+		pattern  string
+		exclude  randchar.Charset
+		expected string
+		entropy  float64
+		err      error
+	}{
+		"license key": {
+			pattern:  "XXXX-XXXX-9999",
+			expected: `^[A-Z0-9]{4}-[A-Z0-9]{4}-[0-9]{4}$`,
+			entropy:  8*5.169925001442312 + 4*3.321928094887362,
+		},
+		"all placeholders": {
+			pattern:  "9aAxX*#",
+			expected: `^[0-9][a-z][A-Z][a-z0-9][A-Z0-9][a-zA-Z0-9][!@#$%^*\-_+=.,?]$`,
+			entropy:  3.321928094887362 + 4.700439718141092 + 4.700439718141092 + 5.169925001442312 + 5.169925001442312 + 5.954196310386876 + 3.807354922057604,
+		},
+		"escaped placeholder": {
+			pattern:  `\X-\\-99`,
+			expected: `^X-\\-[0-9]{2}$`,
+			entropy:  2 * 3.321928094887362,
+		},
+		"exclude similar": {
+			pattern:  "9999",
+			exclude:  similarCharacters,
+			expected: `^[2-9]{4}$`,
+			entropy:  4 * 3,
+		},
+		"no placeholders": {
+			pattern: "---",
+			err:     ErrInvalidPattern("---", "it does not contain any placeholders"),
+		},
+		"trailing escape": {
+			pattern: `99\`,
+			err:     ErrInvalidPattern(`99\`, "it ends with an unescaped \\"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			generator, err := newPatternGenerator(tc.pattern, tc.exclude)
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			actual, err := generator.Generate(defaultLength)
+			assert.OK(t, err)
+			if !regexp.MustCompile(tc.expected).Match(actual) {
+				t.Errorf("generated secret %s does not match %s", actual, tc.expected)
+			}
+			assert.Equal(t, generator.Entropy(defaultLength), tc.entropy)
+		})
+	}
+}
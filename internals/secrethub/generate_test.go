@@ -9,6 +9,7 @@ import (
 	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/assert"
 	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/randchar"
 	randchargeneratorfakes "github.com/secrethub/secrethub-go/pkg/randchar/fakes"
 	"github.com/secrethub/secrethub-go/pkg/secrethub"
 	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
@@ -18,6 +19,16 @@ func newIntValue(v int) intValue {
 	return intValue{v: &v}
 }
 
+// fakeEntropyGenerator is a fake generator that reports the entropy of the secrets it generates.
+type fakeEntropyGenerator struct {
+	randchargeneratorfakes.FakeRandomGenerator
+	entropy float64
+}
+
+func (g fakeEntropyGenerator) Entropy(int) float64 {
+	return g.entropy
+}
+
 func TestGenerateSecretCommand_run(t *testing.T) {
 	testErr := errio.Namespace("test").Code("test").Error("test error")
 
@@ -128,6 +139,38 @@ func TestGenerateSecretCommand_run(t *testing.T) {
 			},
 			err: errors.New("unexpected namespace/repo/secret2"),
 		},
+		"entropy reported": {
+			cmd: GenerateSecretCommand{
+				generator: fakeEntropyGenerator{
+					FakeRandomGenerator: randchargeneratorfakes.FakeRandomGenerator{
+						Ret: []byte("random generated secret"),
+					},
+					entropy: 130.99,
+				},
+				firstArg:   "namespace/repo/secret",
+				minEntropy: 128,
+			},
+			writeFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+				return &api.SecretVersion{Version: 1}, nil
+			},
+			path: "namespace/repo/secret",
+			data: []byte("random generated secret"),
+			out: "A randomly generated secret has been written to namespace/repo/secret:1.\n" +
+				"The secret has an entropy of 130 bits.\n",
+		},
+		"entropy too low": {
+			cmd: GenerateSecretCommand{
+				generator: fakeEntropyGenerator{
+					FakeRandomGenerator: randchargeneratorfakes.FakeRandomGenerator{
+						Ret: []byte("random generated secret"),
+					},
+					entropy: 64,
+				},
+				firstArg:   "namespace/repo/secret",
+				minEntropy: 128,
+			},
+			err: ErrEntropyTooLow(64, 128),
+		},
 		"generate error": {
 			cmd: GenerateSecretCommand{
 				generator: randchargeneratorfakes.FakeRandomGenerator{
@@ -198,3 +241,118 @@ func TestGenerateSecretCommand_run(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSecretCommand_before(t *testing.T) {
+	cases := map[string]struct {
+		cmd     GenerateSecretCommand
+		entropy int
+		err     error
+	}{
+		"default charset": {
+			entropy: 130,
+		},
+		"charset with min": {
+			cmd: GenerateSecretCommand{
+				charsetFlag: charsetValue{v: randchar.Alphanumeric},
+				mins:        minRuleValue{v: []minRule{{name: "numeric", count: 2, charset: randchar.Numeric}}},
+			},
+			entropy: 125,
+		},
+		"exclude similar": {
+			cmd: GenerateSecretCommand{
+				charsetFlag:    charsetValue{v: randchar.Numeric},
+				mins:           minRuleValue{v: []minRule{{name: "numeric", count: 2, charset: randchar.Numeric}}},
+				excludeSimilar: true,
+			},
+			entropy: 66,
+		},
+		"min counts equal to length": {
+			cmd: GenerateSecretCommand{
+				charsetFlag: charsetValue{v: randchar.Numeric},
+				mins:        minRuleValue{v: []minRule{{name: "numeric", count: defaultLength, charset: randchar.Numeric}}},
+			},
+			entropy: 73,
+		},
+		"min counts exceed length": {
+			cmd: GenerateSecretCommand{
+				lengthFlag: newIntValue(4),
+				mins: minRuleValue{v: []minRule{
+					{name: "numeric", count: 3, charset: randchar.Numeric},
+					{name: "lowercase", count: 2, charset: randchar.Lowercase},
+				}},
+			},
+			err: ErrMinCountsExceedLength(5, 4),
+		},
+		"min charset excluded by exclude similar": {
+			cmd: GenerateSecretCommand{
+				mins:           minRuleValue{v: []minRule{{name: "ambiguous", count: 1, charset: similarCharacters}}},
+				excludeSimilar: true,
+			},
+			err: ErrMinCharsetExcluded("ambiguous:1", "ambiguous"),
+		},
+		"passphrase": {
+			cmd: GenerateSecretCommand{
+				passphrase: true,
+				words:      6,
+				separator:  "-",
+			},
+			entropy: 77,
+		},
+		"pattern": {
+			cmd: GenerateSecretCommand{
+				pattern: "9999",
+			},
+			entropy: 13,
+		},
+		"passphrase and pattern": {
+			cmd: GenerateSecretCommand{
+				passphrase: true,
+				pattern:    "9999",
+			},
+			err: ErrPassphraseAndPattern,
+		},
+		"passphrase with length": {
+			cmd: GenerateSecretCommand{
+				passphrase: true,
+				words:      6,
+				lengthFlag: newIntValue(24),
+			},
+			err: ErrCharsetFlagsNotApplicable("--passphrase"),
+		},
+		"pattern with charset": {
+			cmd: GenerateSecretCommand{
+				pattern:     "9999",
+				charsetFlag: charsetValue{v: randchar.Alphanumeric},
+			},
+			err: ErrCharsetFlagsNotApplicable("--pattern"),
+		},
+		"passphrase with exclude similar": {
+			cmd: GenerateSecretCommand{
+				passphrase:     true,
+				words:          6,
+				excludeSimilar: true,
+			},
+			err: ErrExcludeSimilarPassphrase,
+		},
+		"passphrase without words": {
+			cmd: GenerateSecretCommand{
+				passphrase: true,
+			},
+			err: ErrInvalidWordCount,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cmd.before()
+
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+			generator, ok := tc.cmd.generator.(entropyReporter)
+			assert.Equal(t, ok, true)
+			assert.Equal(t, int(generator.Entropy(defaultLength)), tc.entropy)
+		})
+	}
+}
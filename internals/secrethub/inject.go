@@ -22,12 +22,19 @@ import (
 var (
 	ErrUnknownTemplateVersion = errMain.Code("unknown_template_version").ErrorPref("unknown template version: '%s' supported versions are 1, 2 and latest")
 	ErrReadFile               = errMain.Code("in_file_read_error").ErrorPref("could not read the input file %s: %s")
+	ErrOutDirRequired         = errMain.Code("out_dir_required").Error("the --out-dir flag is required when using --in-dir")
+	ErrInDirRequired          = errMain.Code("in_dir_required").Error("the --in-dir flag is required when using --out-dir")
+	ErrInvalidIgnorePattern   = errMain.Code("invalid_ignore_pattern").ErrorPref("invalid --ignore pattern %s: %s")
+	ErrInjectTemplateFile     = errMain.Code("inject_template_file_error").ErrorPref("could not inject secrets into %s: %s")
 )
 
 // InjectCommand is a command to read a secret.
 type InjectCommand struct {
 	outFile                       string
 	inFile                        string
+	outDir                        string
+	inDir                         string
+	ignore                        []string
 	fileMode                      filemode.FileMode
 	force                         bool
 	io                            ui.IO
@@ -67,11 +74,14 @@ func (cmd *InjectCommand) Register(r command.Registerer) {
 	clause.Flag("in-file", "The filename of a template file to inject.").Short('i').StringVar(&cmd.inFile)
 	clause.Flag("out-file", "Write the injected template to a file instead of stdout.").Short('o').StringVar(&cmd.outFile)
 	clause.Flag("file", "").Hidden().StringVar(&cmd.outFile) // Alias of --out-file (for backwards compatibility)
-	clause.Flag("file-mode", "Set filemode for the output file if it does not yet exist. Defaults to 0600 (read and write for current user) and is ignored without the --out-file flag.").Default("0600").SetValue(&cmd.fileMode)
+	clause.Flag("in-dir", "A directory of template files to inject. Every file in the directory and its subdirectories is injected into the same relative path in the --out-dir directory.").StringVar(&cmd.inDir)
+	clause.Flag("out-dir", "The directory to write the injected templates of the --in-dir directory to.").StringVar(&cmd.outDir)
+	clause.Flag("ignore", "A glob pattern of files in the --in-dir directory that should be copied without injecting secrets, e.g. --ignore '*.png'. Patterns are matched against both the path relative to --in-dir and the file name. Can be used multiple times.").StringsVar(&cmd.ignore)
+	clause.Flag("file-mode", "Set filemode for the output file(s) if they do not yet exist. Defaults to 0600 (read and write for current user) and is ignored without the --out-file or --out-dir flag.").Default("0600").SetValue(&cmd.fileMode)
 	clause.Flag("var", "Define the value for a template variable with `VAR=VALUE`, e.g. --var env=prod").Short('v').StringMapVar(&cmd.templateVars)
 	clause.Flag("template-version", "The template syntax version to be used. The options are v1, v2, latest or auto to automatically detect the version.").Default("auto").StringVar(&cmd.templateVersion)
 	clause.Flag("no-prompt", "Do not prompt when a template variable is missing and return an error instead.").BoolVar(&cmd.dontPromptMissingTemplateVars)
-	clause.Flag("force", "Overwrite the output file if it already exists, without prompting for confirmation. This flag is ignored if no --out-file is supplied.").Short('f').BoolVar(&cmd.force)
+	clause.Flag("force", "Overwrite the output file(s) if they already exist, without prompting for confirmation. This flag is ignored if no --out-file or --out-dir is supplied.").Short('f').BoolVar(&cmd.force)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -82,6 +92,10 @@ func (cmd *InjectCommand) Run() error {
 		return ErrFlagsConflict("--clip and --file")
 	}
 
+	if cmd.inDir != "" || cmd.outDir != "" {
+		return cmd.runDir()
+	}
+
 	var err error
 	var raw []byte
 
@@ -101,29 +115,22 @@ func (cmd *InjectCommand) Run() error {
 		}
 	}
 
-	osEnv, _ := parseKeyValueStringsToMap(cmd.osEnv)
-
-	var templateVariableReader tpl.VariableReader
-	templateVariableReader, err = newVariableReader(osEnv, cmd.templateVars)
+	templateVariableReader, err := cmd.variableReader()
 	if err != nil {
 		return err
 	}
 
-	if !cmd.dontPromptMissingTemplateVars {
-		templateVariableReader = newPromptMissingVariableReader(templateVariableReader, cmd.io)
-	}
-
-	parser, err := getTemplateParser(raw, cmd.templateVersion)
+	template, err := parseTemplate(raw, cmd.templateVersion)
 	if err != nil {
 		return err
 	}
 
-	template, err := parser.Parse(string(raw), 1, 1)
-	if err != nil {
-		return err
-	}
+	sr := prefetchSecrets(newSecretReader(cmd.newClient), func(sr tpl.SecretReader) {
+		// Errors are returned when the template is evaluated with the prefetched secrets below.
+		_, _ = template.Evaluate(templateVariableReader, sr)
+	})
 
-	injected, err := template.Evaluate(templateVariableReader, newSecretReader(cmd.newClient))
+	injected, err := template.Evaluate(templateVariableReader, sr)
 	if err != nil {
 		return err
 	}
@@ -178,3 +185,18 @@ func (cmd *InjectCommand) Run() error {
 
 	return nil
 }
+
+// variableReader returns the reader for the template variables of the templates to inject.
+func (cmd *InjectCommand) variableReader() (tpl.VariableReader, error) {
+	osEnv, _ := parseKeyValueStringsToMap(cmd.osEnv)
+
+	templateVariableReader, err := newVariableReader(osEnv, cmd.templateVars)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cmd.dontPromptMissingTemplateVars {
+		return newPromptMissingVariableReader(templateVariableReader, cmd.io), nil
+	}
+	return templateVariableReader, nil
+}
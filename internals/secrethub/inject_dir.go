@@ -0,0 +1,250 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/posix"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+)
+
+// injectedFile is a file in the --in-dir directory that is ready to be written to the --out-dir directory.
+type injectedFile struct {
+	// path is the path of the file relative to the input and output directory.
+	path string
+	data []byte
+}
+
+// runDir injects all templates in the input directory and writes them to the output directory.
+func (cmd *InjectCommand) runDir() error {
+	if cmd.inDir == "" {
+		return ErrInDirRequired
+	}
+	if cmd.outDir == "" {
+		return ErrOutDirRequired
+	}
+	if cmd.inFile != "" || cmd.outFile != "" || cmd.useClipboard {
+		return ErrFlagsConflict("--in-dir, --out-dir and --in-file, --out-file or --clip")
+	}
+
+	for _, pattern := range cmd.ignore {
+		_, err := filepath.Match(pattern, "")
+		if err != nil {
+			return ErrInvalidIgnorePattern(pattern, err)
+		}
+	}
+
+	varReader, err := cmd.variableReader()
+	if err != nil {
+		return err
+	}
+
+	dirs, files, err := cmd.injectDir(varReader, newSecretReader(cmd.newClient))
+	if err != nil {
+		return err
+	}
+
+	var existing int
+	for _, file := range files {
+		_, err := os.Stat(filepath.Join(cmd.outDir, file.path))
+		if err == nil {
+			existing++
+		}
+	}
+
+	if existing > 0 && !cmd.force {
+		if cmd.io.IsOutputPiped() {
+			return ErrFileAlreadyExists
+		}
+
+		confirmed, err := ui.AskYesNo(
+			cmd.io,
+			fmt.Sprintf(
+				"%s in %s already exist, overwrite them?",
+				pluralize("file", "files", existing),
+				cmd.outDir,
+			),
+			ui.DefaultNo,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Fprintln(cmd.io.Output(), "Aborting.")
+			return nil
+		}
+	}
+
+	for _, dir := range dirs {
+		err = os.MkdirAll(filepath.Join(cmd.outDir, dir), dirFileMode(cmd.fileMode.FileMode()))
+		if err != nil {
+			return ErrCannotWrite(filepath.Join(cmd.outDir, dir), err)
+		}
+	}
+
+	for _, file := range files {
+		path := filepath.Join(cmd.outDir, file.path)
+		err = ioutil.WriteFile(path, file.data, cmd.fileMode.FileMode())
+		if err != nil {
+			return ErrCannotWrite(path, err)
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return ErrCannotWrite(err)
+		}
+
+		fmt.Fprintf(cmd.io.Output(), "%s\n", absPath)
+	}
+
+	return nil
+}
+
+// templateFile is a file in the --in-dir directory.
+type templateFile struct {
+	// path is the path of the file relative to the input directory.
+	path string
+	raw  []byte
+	// template is the parsed template or nil if the file is ignored.
+	template tpl.Template
+}
+
+// injectDir injects all templates in the input directory, without writing them.
+// It returns the relative paths of all directories in the input directory, with parents
+// before their children, and the injected files. Files matching one of the ignore patterns
+// are returned verbatim.
+func (cmd *InjectCommand) injectDir(varReader tpl.VariableReader, sr tpl.SecretReader) ([]string, []injectedFile, error) {
+	dirs, templates, err := cmd.readDir()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// All templates share the same secret reader, so that every secret is only read once.
+	sr = prefetchSecrets(sr, func(sr tpl.SecretReader) {
+		for _, file := range templates {
+			if file.template != nil {
+				// Errors are returned when the templates are evaluated with the prefetched secrets below.
+				_, _ = file.template.Evaluate(varReader, sr)
+			}
+		}
+	})
+
+	files := make([]injectedFile, len(templates))
+	for i, file := range templates {
+		files[i] = injectedFile{
+			path: file.path,
+			data: file.raw,
+		}
+		if file.template == nil {
+			continue
+		}
+
+		injected, err := file.template.Evaluate(varReader, sr)
+		if err != nil {
+			return nil, nil, ErrInjectTemplateFile(filepath.Join(cmd.inDir, file.path), err)
+		}
+		files[i].data = posix.AddNewLine([]byte(injected))
+	}
+
+	return dirs, files, nil
+}
+
+// readDir reads and parses all files in the input directory. It returns the relative
+// paths of all directories in the input directory, with parents before their children,
+// and the files in the directory.
+func (cmd *InjectCommand) readDir() ([]string, []templateFile, error) {
+	absOutDir, err := filepath.Abs(cmd.outDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dirs := []string{}
+	files := []templateFile{}
+	err = filepath.Walk(cmd.inDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(cmd.inDir, path)
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			// Do not inject the output of a previous run when the output directory
+			// is located within the input directory.
+			absPath, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if absPath == absOutDir {
+				return filepath.SkipDir
+			}
+
+			dirs = append(dirs, relPath)
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return ErrReadFile(path, err)
+		}
+
+		file := templateFile{
+			path: relPath,
+			raw:  raw,
+		}
+		if !cmd.isIgnored(relPath) {
+			file.template, err = parseTemplate(raw, cmd.templateVersion)
+			if err != nil {
+				return ErrInjectTemplateFile(path, err)
+			}
+		}
+
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dirs, files, nil
+}
+
+// isIgnored returns whether the file at the given path relative to the input directory
+// matches one of the ignore patterns.
+func (cmd *InjectCommand) isIgnored(relPath string) bool {
+	for _, pattern := range cmd.ignore {
+		for _, name := range []string{filepath.ToSlash(relPath), filepath.Base(relPath)} {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseTemplate parses the raw template with the parser for the given template version.
+func parseTemplate(raw []byte, templateVersion string) (tpl.Template, error) {
+	parser, err := getTemplateParser(raw, templateVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.Parse(string(raw), 1, 1)
+}
+
+// dirFileMode returns the file mode for directories containing files with the given
+// file mode. It adds the execute permission for everyone that can read the files.
+func dirFileMode(fileMode os.FileMode) os.FileMode {
+	mode := fileMode.Perm()
+	return mode | (mode&0444)>>2
+}
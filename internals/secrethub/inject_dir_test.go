@@ -0,0 +1,198 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/filemode"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestInjectCommand_runDir(t *testing.T) {
+	cases := map[string]struct {
+		files    map[string]string
+		existing map[string]string
+		ignore   []string
+		force    bool
+		expected map[string]string
+		reads    map[string]int
+		err      error
+	}{
+		"tree is mirrored": {
+			files: map[string]string{
+				"app.conf":            "password={{ company/repo/db }}",
+				"nested/db/db.conf":   "user={{ company/repo/user }}\npassword={{ company/repo/db }}",
+				"nested/logo.png":     "{{ not a template }}",
+				"nested/db/logo.png":  "{{ not a template }}",
+				"nested/plain/readme": "hello world",
+			},
+			ignore: []string{"*.png"},
+			expected: map[string]string{
+				"app.conf":            "password=secret\n",
+				"nested/db/db.conf":   "user=admin\npassword=secret\n",
+				"nested/logo.png":     "{{ not a template }}",
+				"nested/db/logo.png":  "{{ not a template }}",
+				"nested/plain/readme": "hello world\n",
+			},
+			reads: map[string]int{
+				"company/repo/db":   1,
+				"company/repo/user": 1,
+			},
+		},
+		"ignore relative path": {
+			files: map[string]string{
+				"static/index.html": "{{ not a template }}",
+				"index.html":        "{{ company/repo/user }}",
+			},
+			ignore: []string{"static/*"},
+			expected: map[string]string{
+				"static/index.html": "{{ not a template }}",
+				"index.html":        "admin\n",
+			},
+			reads: map[string]int{
+				"company/repo/user": 1,
+			},
+		},
+		"overwrite with force": {
+			files: map[string]string{
+				"app.conf": "{{ company/repo/user }}",
+			},
+			existing: map[string]string{
+				"app.conf": "old",
+			},
+			force: true,
+			expected: map[string]string{
+				"app.conf": "admin\n",
+			},
+			reads: map[string]int{
+				"company/repo/user": 1,
+			},
+		},
+		"existing file without force": {
+			files: map[string]string{
+				"app.conf": "{{ company/repo/user }}",
+			},
+			existing: map[string]string{
+				"app.conf": "old",
+			},
+			expected: map[string]string{
+				"app.conf": "old",
+			},
+			reads: map[string]int{
+				"company/repo/user": 1,
+			},
+			err: ErrFileAlreadyExists,
+		},
+		"invalid ignore pattern": {
+			files:    map[string]string{},
+			ignore:   []string{"[a-"},
+			expected: map[string]string{},
+			reads:    map[string]int{},
+			err:      ErrInvalidIgnorePattern("[a-", filepath.ErrBadPattern),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			inDir := filepath.Join(dir, "in")
+			outDir := filepath.Join(dir, "out")
+			writeTestFiles(t, inDir, tc.files)
+			writeTestFiles(t, outDir, tc.existing)
+
+			reads := map[string]int{}
+			io := fakeui.NewIO(t)
+			io.Out.Piped = true
+			cmd := InjectCommand{
+				inDir:                         inDir,
+				outDir:                        outDir,
+				ignore:                        tc.ignore,
+				force:                         tc.force,
+				fileMode:                      filemode.New(0600),
+				templateVersion:               "auto",
+				dontPromptMissingTemplateVars: true,
+				io:                            io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									reads[path]++
+									secrets := map[string]string{
+										"company/repo/db":   "secret",
+										"company/repo/user": "admin",
+									}
+									return &api.SecretVersion{Data: []byte(secrets[path])}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, reads, tc.reads)
+			assert.Equal(t, readTestFiles(t, outDir), tc.expected)
+		})
+	}
+}
+
+func TestDirFileMode(t *testing.T) {
+	cases := map[os.FileMode]os.FileMode{
+		0600: 0700,
+		0640: 0750,
+		0644: 0755,
+		0400: 0500,
+	}
+
+	for fileMode, expected := range cases {
+		assert.Equal(t, dirFileMode(fileMode), expected)
+	}
+}
+
+// writeTestFiles creates the given files relative to the given directory.
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		assert.OK(t, os.MkdirAll(filepath.Dir(path), 0770))
+		assert.OK(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+// readTestFiles returns the contents of all files in the given directory by their relative path.
+func readTestFiles(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = string(content)
+		return nil
+	})
+	assert.OK(t, err)
+	return files
+}
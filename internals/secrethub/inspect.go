@@ -17,6 +17,7 @@ type InspectCommand struct {
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
+	format        string
 }
 
 // NewInspectCommand creates a new InspectCommand.
@@ -33,6 +34,8 @@ func (cmd *InspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Print details of a resource.")
 	clause.Arg("repo or secret-path", "Path to the repository or the secret to inspect "+repoPathPlaceHolder+" or "+secretPathOptionalVersionPlaceHolder).Required().SetValue(&cmd.path)
 
+	registerInspectFormatFlag(clause).StringVar(&cmd.format)
+
 	command.BindAction(clause, cmd.Run)
 }
 
@@ -45,24 +48,29 @@ func (cmd *InspectCommand) Run() error {
 			cmd.newClient,
 		)
 		repoInspectCmd.path = repoPath
+		repoInspectCmd.format = cmd.format
 		return repoInspectCmd.Run()
 	}
 
 	secretPath, err := cmd.path.ToSecretPath()
 	if err == nil {
 		if secretPath.HasVersion() {
-			return NewInspectSecretVersionCommand(
+			inspectSecretVersionCmd := NewInspectSecretVersionCommand(
 				secretPath,
 				cmd.io,
 				cmd.newClient,
-			).Run()
+			)
+			inspectSecretVersionCmd.format = cmd.format
+			return inspectSecretVersionCmd.Run()
 		}
 
-		return NewInspectSecretCommand(
+		inspectSecretCmd := NewInspectSecretCommand(
 			secretPath,
 			cmd.io,
 			cmd.newClient,
-		).Run()
+		)
+		inspectSecretCmd.format = cmd.format
+		return inspectSecretCmd.Run()
 	}
 
 	return ErrInspectResourceNotSupported
@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 
 	"github.com/secrethub/secrethub-go/internals/api"
@@ -15,6 +12,7 @@ type InspectSecretCommand struct {
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
+	format        string
 }
 
 // NewInspectSecretCommand crates a new InspectSecretCommand
@@ -44,14 +42,7 @@ func (cmd *InspectSecretCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newSecretOutput(secret.Secret, versions, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return printStructured(cmd.io.Output(), cmd.format, newSecretOutput(secret.Secret, versions, cmd.timeFormatter))
 }
 
 // newSecretOutput returns the JSON output of a secret.
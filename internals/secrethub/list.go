@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"sort"
-	"text/tabwriter"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -19,6 +18,7 @@ type LsCommand struct {
 	path          api.Path
 	quiet         bool
 	useTimestamps bool
+	format        string
 	io            ui.IO
 	newClient     newClientFunc
 }
@@ -38,18 +38,25 @@ func (cmd *LsCommand) Register(r command.Registerer) {
 	clause.Arg("path", "The path to list contents of").SetValue(&cmd.path)
 	clause.Flag("quiet", "Only print paths.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFormatFlag(clause, lsItem{}).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run lists a repo, secret or namespace.
 func (cmd *LsCommand) Run() error {
-	timeFormatter := NewTimeFormatter(cmd.useTimestamps)
+	err := validateOutputFormat(cmd.format)
+	if err != nil {
+		return err
+	}
+
+	timeFormatter := NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format))
 
 	if cmd.path == "" {
 		repoLSCommand := NewRepoLSCommand(cmd.io, cmd.newClient)
 		repoLSCommand.quiet = cmd.quiet
 		repoLSCommand.useTimestamps = cmd.useTimestamps
+		repoLSCommand.format = cmd.format
 		return repoLSCommand.Run()
 	}
 
@@ -71,7 +78,7 @@ func (cmd *LsCommand) Run() error {
 			return err
 		}
 
-		err = printVersions(cmd.io.Output(), cmd.quiet, timeFormatter, version)
+		err = printVersions(cmd.io.Output(), cmd.quiet, cmd.format, timeFormatter, version)
 		if err != nil {
 			return err
 		}
@@ -88,7 +95,7 @@ func (cmd *LsCommand) Run() error {
 		} else if err != nil && !api.IsErrNotFound(err) {
 			return err
 		} else if err == nil {
-			err = printDir(cmd.io.Output(), cmd.quiet, dirFS.RootDir, timeFormatter)
+			err = printDir(cmd.io.Output(), cmd.quiet, cmd.format, dirFS.RootDir, timeFormatter)
 			if err != nil {
 				return err
 			}
@@ -106,7 +113,7 @@ func (cmd *LsCommand) Run() error {
 			return err
 		}
 
-		err = printVersions(cmd.io.Output(), cmd.quiet, timeFormatter, versions...)
+		err = printVersions(cmd.io.Output(), cmd.quiet, cmd.format, timeFormatter, versions...)
 		if err != nil {
 			return err
 		}
@@ -120,6 +127,7 @@ func (cmd *LsCommand) Run() error {
 			workspace:     workspace,
 			useTimestamps: cmd.useTimestamps,
 			quiet:         cmd.quiet,
+			format:        cmd.format,
 			io:            cmd.io,
 			newClient:     cmd.newClient,
 		}
@@ -133,18 +141,23 @@ func (cmd *LsCommand) Run() error {
 }
 
 // printVersions prints out secret versions in long or short format.
-func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, versions ...*api.SecretVersion) error {
+func printVersions(w io.Writer, quiet bool, format string, timeFormatter TimeFormatter, versions ...*api.SecretVersion) error {
 	if quiet {
 		for _, version := range versions {
 			fmt.Fprintf(w, "%s\n", version.Name())
 		}
 	} else {
-		w := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
+		formatter, err := newListFormatter(format, w, 2, lsHeader)
+		if err != nil {
+			return err
+		}
 		for _, version := range versions {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", version.Name(), version.Status, timeFormatter.Format(version.CreatedAt.Local()))
+			err = writeLsItem(formatter, version.Name(), version.Status, timeFormatter.Format(version.CreatedAt.Local()))
+			if err != nil {
+				return err
+			}
 		}
-		err := w.Flush()
+		err = formatter.Flush()
 		if err != nil {
 			return err
 		}
@@ -153,7 +166,7 @@ func printVersions(w io.Writer, quiet bool, timeFormatter TimeFormatter, version
 }
 
 // printDir prints out directory contents in long or short format.
-func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter) error {
+func printDir(w io.Writer, quiet bool, format string, dir *api.Dir, timeFormatter TimeFormatter) error {
 	sort.Sort(api.SortDirByName(dir.SubDirs))
 	sort.Sort(api.SortSecretByName(dir.Secrets))
 
@@ -165,18 +178,45 @@ func printDir(w io.Writer, quiet bool, dir *api.Dir, timeFormatter TimeFormatter
 			fmt.Fprintf(w, "%s\n", secret.Name)
 		}
 	} else {
-		tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
-		fmt.Fprintf(tw, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
+		formatter, err := newListFormatter(format, w, 2, lsHeader)
+		if err != nil {
+			return err
+		}
 		for _, dir := range dir.SubDirs {
-			fmt.Fprintf(tw, "%s/\t%s\t%s\n", dir.Name, dir.Status, timeFormatter.Format(dir.CreatedAt.Local()))
+			err = writeLsItem(formatter, dir.Name+"/", dir.Status, timeFormatter.Format(dir.CreatedAt.Local()))
+			if err != nil {
+				return err
+			}
 		}
 		for _, secret := range dir.Secrets {
-			fmt.Fprintf(tw, "%s\t%s\t%s\n", secret.Name, secret.Status, timeFormatter.Format(secret.CreatedAt.Local()))
+			err = writeLsItem(formatter, secret.Name, secret.Status, timeFormatter.Format(secret.CreatedAt.Local()))
+			if err != nil {
+				return err
+			}
 		}
-		err := tw.Flush()
+		err = formatter.Flush()
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// lsHeader contains the names of the columns printed by ls and repo ls in the table format.
+var lsHeader = []string{"name", "status", "created"}
+
+// lsItem is the json and yaml format of a directory, secret, secret version or repository listed by ls and repo ls.
+type lsItem struct {
+	Name    string `json:"Name"`
+	Status  string `json:"Status"`
+	Created string `json:"Created"`
+}
+
+// writeLsItem writes a directory, secret, secret version or repository listed by ls and repo ls.
+func writeLsItem(formatter listItemFormatter, name, status, created string) error {
+	return formatter.Write([]string{name, status, created}, lsItem{
+		Name:    name,
+		Status:  status,
+		Created: created,
+	})
+}
@@ -1,14 +1,229 @@
 package secrethub
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
+	"text/tabwriter"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrInvalidOutputFormat = errMain.Code("invalid_output_format").ErrorPref("invalid output format: %s. Options are table, json and yaml")
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
 )
 
 type listFormatter interface {
 	Write([]string) error
+	// Flush writes any buffered rows to the output.
+	Flush() error
+}
+
+// listItemFormatter formats the items of a list command in the output format chosen with --output-format.
+type listItemFormatter interface {
+	// Write writes an item. The table format writes the cells as a row.
+	// The json and yaml formats write the item, which should be a struct with fixed json tags.
+	Write(cells []string, item interface{}) error
+	// Flush writes any buffered items to the output.
+	Flush() error
+}
+
+// newListFormatter returns a formatter of list items in the given output format.
+// In the table format, the header contains the names of the columns, which are separated by the given padding.
+// The header is not used in the json and yaml formats, so that rewording it does not change their fields.
+func newListFormatter(format string, writer io.Writer, padding int, header []string) (listItemFormatter, error) {
+	switch format {
+	case formatTable, "":
+		return tableItemFormatter{columns: newColumnFormatter(writer, padding, header)}, nil
+	case formatJSON:
+		return jsonItemFormatter{encoder: json.NewEncoder(writer)}, nil
+	case formatYAML:
+		return yamlItemFormatter{writer: writer}, nil
+	default:
+		return nil, ErrInvalidOutputFormat(format)
+	}
+}
+
+// describeListItem returns a description of the fields of the given list item in the json and yaml formats.
+// The names of the fields are taken from their json tags.
+func describeListItem(item interface{}) string {
+	t := reflect.TypeOf(item)
+	fields := make([]string, t.NumField())
+	for i := range fields {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		fieldType := "string"
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			fieldType = "boolean"
+		case reflect.Int, reflect.Int64:
+			fieldType = "number"
+		}
+		fields[i] = fmt.Sprintf("%s (%s)", name, fieldType)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// validateOutputFormat returns an error if the given output format is not supported.
+func validateOutputFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML, "":
+		return nil
+	default:
+		return ErrInvalidOutputFormat(format)
+	}
+}
+
+// isStructuredFormat returns whether the output format is meant to be parsed by scripts.
+func isStructuredFormat(format string) bool {
+	return format == formatJSON || format == formatYAML
+}
+
+// printStructured prints the given value as indented json or as yaml.
+// The field names in the yaml output are the same as the ones in the json output.
+func printStructured(w io.Writer, format string, value interface{}) error {
+	switch format {
+	case formatJSON, "":
+		output, err := cli.PrettyJSON(value)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, output)
+		return err
+	case formatYAML:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		ordered, err := decodeOrderedJSON(decoder)
+		if err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(ordered)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	default:
+		return ErrInvalidOutputFormat(format)
+	}
+}
+
+// decodeOrderedJSON decodes the next json value from the decoder
+// and keeps the order of the fields of objects by decoding them into a yaml.MapSlice.
+func decodeOrderedJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		res := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err = decoder.Token()
+		return res, err
+	case json.Delim('['):
+		res := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrderedJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		_, err = decoder.Token()
+		return res, err
+	default:
+		if number, ok := token.(json.Number); ok {
+			if i, err := number.Int64(); err == nil {
+				return i, nil
+			}
+			return number.Float64()
+		}
+		return token, nil
+	}
+}
+
+// newColumnFormatter returns a list formatter that aligns the values in columns,
+// with the given header as the first row.
+func newColumnFormatter(writer io.Writer, padding int, header []string) *columnFormatter {
+	return &columnFormatter{
+		writer: tabwriter.NewWriter(writer, 0, padding, padding, ' ', 0),
+		header: header,
+	}
+}
+
+// columnFormatter formats the given rows in columns that are aligned with tabs.
+type columnFormatter struct {
+	writer        *tabwriter.Writer
+	header        []string
+	headerPrinted bool
+}
+
+// Write writes the given row. The header is written before the first row.
+func (f *columnFormatter) Write(values []string) error {
+	err := f.writeHeader()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(f.writer, strings.Join(values, "\t"))
+	return err
+}
+
+// Flush writes the aligned columns to the output.
+// If no rows have been written, only the header is written.
+func (f *columnFormatter) Flush() error {
+	err := f.writeHeader()
+	if err != nil {
+		return err
+	}
+	return f.writer.Flush()
+}
+
+// writeHeader writes the header in uppercase if it has not been written yet.
+func (f *columnFormatter) writeHeader() error {
+	if f.headerPrinted {
+		return nil
+	}
+
+	header := make([]string, len(f.header))
+	for i, name := range f.header {
+		header[i] = strings.ToUpper(name)
+	}
+	_, err := fmt.Fprintln(f.writer, strings.Join(header, "\t"))
+	if err != nil {
+		return err
+	}
+	f.headerPrinted = true
+	return nil
 }
 
 func newLineFormatter(writer io.Writer) lineFormatter {
@@ -26,6 +241,11 @@ func (l lineFormatter) Write(line []string) error {
 	return err
 }
 
+// Flush implements the listFormatter interface. Lines are written immediately.
+func (l lineFormatter) Flush() error {
+	return nil
+}
+
 // newJSONFormatter returns a table formatter that formats the given table rows as json.
 func newJSONFormatter(writer io.Writer, fieldNames []string) *jsonFormatter {
 	for i := range fieldNames {
@@ -38,7 +258,7 @@ func newJSONFormatter(writer io.Writer, fieldNames []string) *jsonFormatter {
 }
 
 func toPascalCase(s string) string {
-	return strings.ReplaceAll(strings.Title(s), " ", "")
+	return strings.ReplaceAll(strings.Title(strings.ReplaceAll(s, "-", " ")), " ", "")
 }
 
 type jsonFormatter struct {
@@ -61,6 +281,56 @@ func (f *jsonFormatter) Write(values []string) error {
 	return f.encoder.Encode(jsonMap)
 }
 
+// Flush implements the listFormatter interface. Rows are written immediately.
+func (f *jsonFormatter) Flush() error {
+	return nil
+}
+
+// tableItemFormatter writes the cells of list items as rows of aligned columns.
+type tableItemFormatter struct {
+	columns *columnFormatter
+}
+
+// Write writes the cells as a row.
+func (f tableItemFormatter) Write(cells []string, _ interface{}) error {
+	return f.columns.Write(cells)
+}
+
+// Flush writes the aligned columns to the output.
+func (f tableItemFormatter) Flush() error {
+	return f.columns.Flush()
+}
+
+// jsonItemFormatter writes list items as json objects, one per line.
+type jsonItemFormatter struct {
+	encoder *json.Encoder
+}
+
+// Write writes the json representation of the item.
+func (f jsonItemFormatter) Write(_ []string, item interface{}) error {
+	return f.encoder.Encode(item)
+}
+
+// Flush implements the listItemFormatter interface. Items are written immediately.
+func (f jsonItemFormatter) Flush() error {
+	return nil
+}
+
+// yamlItemFormatter writes list items as the elements of a yaml list.
+type yamlItemFormatter struct {
+	writer io.Writer
+}
+
+// Write writes the item as an element of a yaml list, with the fields in the same order as in the json format.
+func (f yamlItemFormatter) Write(_ []string, item interface{}) error {
+	return printStructured(f.writer, formatYAML, []interface{}{item})
+}
+
+// Flush implements the listItemFormatter interface. Items are written immediately.
+func (f yamlItemFormatter) Flush() error {
+	return nil
+}
+
 // newTableFormatter returns a list formatter that formats entries in a table.
 func newTableFormatter(writer io.Writer, tableWidth int, columns []tableColumn) *tableFormatter {
 	return &tableFormatter{
@@ -99,6 +369,11 @@ func (f *tableFormatter) Write(values []string) error {
 	return err
 }
 
+// Flush implements the listFormatter interface. Rows are written immediately.
+func (f *tableFormatter) Flush() error {
+	return nil
+}
+
 // formatRow formats the given table row to fit the configured width by
 // giving each cell an equal width and wrapping the text in cells that exceed it.
 func (f *tableFormatter) formatRow(row []string) []byte {
@@ -1,6 +1,7 @@
 package secrethub
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
@@ -128,3 +129,121 @@ func Test_columnFormatter_formatRow(t *testing.T) {
 		})
 	}
 }
+
+// testListItem is a list item of which the fields differ from the header of the table.
+type testListItem struct {
+	Name    string `json:"Name"`
+	Count   int    `json:"Count"`
+	Enabled bool   `json:"Enabled"`
+}
+
+func TestNewListFormatter(t *testing.T) {
+	cases := map[string]struct {
+		format   string
+		expected string
+		err      error
+	}{
+		"table": {
+			format: formatTable,
+			expected: "NAME    NUMBER OF ITEMS\n" +
+				"foo     1\n" +
+				"bar     20\n",
+		},
+		"default": {
+			format: "",
+			expected: "NAME    NUMBER OF ITEMS\n" +
+				"foo     1\n" +
+				"bar     20\n",
+		},
+		"json": {
+			format: formatJSON,
+			expected: "{\"Name\":\"foo\",\"Count\":1,\"Enabled\":true}\n" +
+				"{\"Name\":\"bar\",\"Count\":20,\"Enabled\":false}\n",
+		},
+		"yaml": {
+			format: formatYAML,
+			expected: "- Name: foo\n" +
+				"  Count: 1\n" +
+				"  Enabled: true\n" +
+				"- Name: bar\n" +
+				"  Count: 20\n" +
+				"  Enabled: false\n",
+		},
+		"invalid": {
+			format: "xml",
+			err:    ErrInvalidOutputFormat("xml"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+
+			formatter, err := newListFormatter(tc.format, &buf, 4, []string{"name", "number of items"})
+			assert.Equal(t, err, tc.err)
+			if err != nil {
+				return
+			}
+
+			assert.OK(t, formatter.Write([]string{"foo", "1"}, testListItem{Name: "foo", Count: 1, Enabled: true}))
+			assert.OK(t, formatter.Write([]string{"bar", "20"}, testListItem{Name: "bar", Count: 20}))
+			assert.OK(t, formatter.Flush())
+
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
+
+func TestDescribeListItem(t *testing.T) {
+	actual := describeListItem(testListItem{})
+
+	assert.Equal(t, actual, "Name (string), Count (number), Enabled (boolean)")
+}
+
+func TestPrintStructured(t *testing.T) {
+	value := struct {
+		Name    string
+		Created string
+		Tags    []string
+	}{
+		Name:    "foo",
+		Created: "2018-01-01T01:01:01+01:00",
+		Tags:    []string{"a", "b"},
+	}
+
+	cases := map[string]struct {
+		format   string
+		expected string
+	}{
+		"json": {
+			format: formatJSON,
+			expected: "{\n" +
+				"    \"Name\": \"foo\",\n" +
+				"    \"Created\": \"2018-01-01T01:01:01+01:00\",\n" +
+				"    \"Tags\": [\n" +
+				"        \"a\",\n" +
+				"        \"b\"\n" +
+				"    ]\n" +
+				"}\n",
+		},
+		"yaml keeps field order": {
+			format: formatYAML,
+			expected: "Name: foo\n" +
+				"Created: \"2018-01-01T01:01:01+01:00\"\n" +
+				"Tags:\n" +
+				"- a\n" +
+				"- b\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			buf := bytes.Buffer{}
+
+			err := printStructured(&buf, tc.format, value)
+
+			assert.OK(t, err)
+			assert.Equal(t, buf.String(), tc.expected)
+		})
+	}
+}
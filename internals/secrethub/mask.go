@@ -0,0 +1,82 @@
+package secrethub
+
+import (
+	"io"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+)
+
+// Errors
+var (
+	ErrReadMaskInput = errMain.Code("mask_input_read_error").ErrorPref("could not read the input to mask: %s")
+)
+
+// MaskCommand masks the secrets of an environment in the data read from stdin and writes the result to stdout.
+type MaskCommand struct {
+	io                   ui.IO
+	newClient            newClientFunc
+	environment          *environment
+	masking              maskingFlags
+	ignoreMissingSecrets bool
+}
+
+// NewMaskCommand creates a new MaskCommand.
+func NewMaskCommand(io ui.IO, newClient newClientFunc) *MaskCommand {
+	return &MaskCommand{
+		io:          io,
+		newClient:   newClient,
+		environment: newEnvironment(io, newClient),
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MaskCommand) Register(r command.Registerer) {
+	clause := r.Command("mask", "Mask secrets in the data read from stdin and write the result to stdout, e.g. kubectl logs <pod> | secrethub mask --secrets-dir <dir>.")
+	clause.HelpLong("The secrets to mask are sourced in the same way as the run command does and are replaced with \"" + maskString + "\". " +
+		"The input is buffered to scan for secrets and can be adjusted using the masking-buffer-period flag. " +
+		"You should regard the masking as a best effort attempt and should always prevent secrets ending up in logs in the first place.")
+	clause.Flag("ignore-missing-secrets", "Do not return an error when a secret does not exist and do not mask it.").BoolVar(&cmd.ignoreMissingSecrets)
+	cmd.masking.register(clause)
+	cmd.environment.register(clause)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run resolves the secrets of the environment and copies stdin to stdout, masking the secrets.
+func (cmd *MaskCommand) Run() error {
+	maskValues, err := cmd.masking.configure()
+	if err != nil {
+		return err
+	}
+
+	// Stdin is the data to mask, so it cannot be used to prompt for missing template variables.
+	cmd.environment.dontPromptMissingTemplateVar = true
+
+	envValues, err := cmd.environment.env()
+	if err != nil {
+		return err
+	}
+
+	_, secrets, err := resolveEnv(envValues, cmd.masking.secretReader(cmd.newClient), cmd.ignoreMissingSecrets)
+	if err != nil {
+		return err
+	}
+
+	m := cmd.masking.newMasker(secrets, maskValues)
+	out := m.AddNamedStream("stdout", cmd.io.Output())
+	go m.Start()
+
+	_, copyErr := io.Copy(out, cmd.io.Input())
+
+	// The masker is stopped also when reading the input failed, so that everything read so far is written.
+	err = m.Stop()
+	if err != nil {
+		return err
+	}
+	if copyErr != nil {
+		return ErrReadMaskInput(copyErr)
+	}
+
+	return cmd.masking.reportMasks(m.Report())
+}
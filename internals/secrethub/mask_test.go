@@ -0,0 +1,124 @@
+package secrethub
+
+import (
+	"os"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestMaskCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		envar                map[string]string
+		maskValues           []string
+		ignoreMissingSecrets bool
+		failOnLeak           bool
+		in                   string
+		out                  string
+		err                  error
+	}{
+		"secrets masked": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/db_password",
+				"API_KEY":     "company/app/api_key",
+			},
+			in:  "connecting with s3cr3t-db\nusing key k3y-4p1 and s3cr3t-db\n",
+			out: "connecting with " + maskString + "\nusing key " + maskString + " and " + maskString + "\n",
+		},
+		"encoded secret masked": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/db_password",
+			},
+			in:  "Authorization: Basic czNjcjN0LWRi\n",
+			out: "Authorization: Basic " + maskString + "\n",
+		},
+		"mask value": {
+			maskValues: []string{"runtime-token"},
+			in:         "token: runtime-token\n",
+			out:        "token: " + maskString + "\n",
+		},
+		"no secrets in input": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/db_password",
+			},
+			in:  "nothing to see here\n",
+			out: "nothing to see here\n",
+		},
+		"missing secret": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/missing",
+			},
+			in:  "nothing to see here\n",
+			err: ErrResolveEnvVar("DB_PASSWORD", "--envar", api.ErrSecretNotFound),
+		},
+		"ignore missing secret": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/db_password",
+				"MISSING":     "company/app/missing",
+			},
+			ignoreMissingSecrets: true,
+			in:                   "password s3cr3t-db\n",
+			out:                  "password " + maskString + "\n",
+		},
+		"fail on leak": {
+			envar: map[string]string{
+				"DB_PASSWORD": "company/app/db_password",
+			},
+			failOnLeak: true,
+			in:         "password s3cr3t-db\n",
+			out:        "password " + maskString + "\n",
+			err:        ErrSecretsLeaked("1 secret"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			io.In.Buffer.WriteString(tc.in)
+
+			cmd := MaskCommand{
+				io: io,
+				environment: &environment{
+					osStat:          osStatFunc("secrethub.env", os.ErrNotExist),
+					templateVersion: "auto",
+					envar:           tc.envar,
+				},
+				masking: maskingFlags{
+					encodings:  defaultMaskEncodings(),
+					values:     tc.maskValues,
+					failOnLeak: tc.failOnLeak,
+				},
+				ignoreMissingSecrets: tc.ignoreMissingSecrets,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									secrets := map[string]string{
+										"company/app/db_password": "s3cr3t-db",
+										"company/app/api_key":     "k3y-4p1",
+									}
+									secret, ok := secrets[path]
+									if !ok {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte(secret)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package secrethub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrCannotMoveVersion = errMain.Code("cannot_move_version").Error("cannot move a single secret version. Use the cp command to copy it")
+	ErrCannotMoveRootDir = errMain.Code("cannot_move_root_dir").Error("cannot move the root directory of a repository. Use the cp command with the -r flag to copy its contents")
+)
+
+// MvCommand moves secrets and directories.
+type MvCommand struct {
+	src       api.Path
+	dst       api.Path
+	recursive bool
+	versions  string
+	dryRun    bool
+	force     bool
+	io        ui.IO
+	newClient newClientFunc
+}
+
+// NewMvCommand creates a new MvCommand.
+func NewMvCommand(io ui.IO, newClient newClientFunc) *MvCommand {
+	return &MvCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *MvCommand) Register(r command.Registerer) {
+	clause := r.Command("mv", "Move a secret or directory, also to another repository. The source is removed after it has been copied.")
+	clause.Alias("move")
+	clause.Arg("src-path", "The path to the secret or directory to move (<namespace>/<repo>/<path>)").Required().SetValue(&cmd.src)
+	clause.Arg("dst-path", "The path to move to. When it is an existing directory, the source is moved into it (<namespace>/<repo>[/<path>])").Required().SetValue(&cmd.dst)
+	clause.Flag("recursive", "Move directories and their contents recursively.").Short('r').BoolVar(&cmd.recursive)
+	registerCopyVersionsFlag(clause).Default(copyVersionsAll).StringVar(&cmd.versions)
+	registerDryRunFlag(clause).BoolVar(&cmd.dryRun)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run copies the secret or directory to the destination and removes the source.
+func (cmd *MvCommand) Run() error {
+	allVersions, err := parseCopyVersions(cmd.versions)
+	if err != nil {
+		return err
+	}
+
+	if cmd.src.HasVersion() {
+		return ErrCannotMoveVersion
+	}
+
+	_, err = cmd.src.ToRepoPath()
+	if err == nil {
+		return ErrCannotMoveRootDir
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	plan, err := planCopy(client, cmd.src, cmd.dst, cmd.recursive)
+	if err != nil {
+		return err
+	}
+
+	if cmd.dryRun {
+		plan.print(cmd.io.Output(), allVersions)
+		fmt.Fprintf(cmd.io.Output(), "Remove %s\n", cmd.src)
+		return nil
+	}
+
+	kind := "secret"
+	name := api.SecretPath(cmd.src).GetSecret()
+	if plan.isDir {
+		kind = "directory"
+		name = api.DirPath(cmd.src).GetDirName()
+	}
+
+	overwriteWarning := ""
+	if overwritten := plan.overwrittenSecrets(); len(overwritten) > 0 {
+		overwriteWarning = fmt.Sprintf("It will also write over %s: %s. ",
+			pluralize("existing secret", "existing secrets", len(overwritten)), strings.Join(overwritten, ", "))
+	}
+
+	ok, err := askRmConfirmation(
+		cmd.io,
+		fmt.Sprintf("This will move the %s %s to %s and permanently remove it from %s. %s"+
+			"Please type in the name of the %s to confirm", kind, cmd.src, plan.dst, cmd.src, overwriteWarning, kind),
+		cmd.force,
+		name,
+		cmd.src.String(),
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	err = plan.execute(client, cmd.io.Output(), allVersions)
+	if err != nil {
+		return err
+	}
+
+	if plan.isDir {
+		err = client.Dirs().Delete(cmd.src.String())
+	} else {
+		err = client.Secrets().Delete(cmd.src.String())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.io.Output(), "Remove %s\n", cmd.src)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package secrethub
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+func TestMvCommand_Run(t *testing.T) {
+	cases := map[string]struct {
+		src       api.Path
+		dst       api.Path
+		recursive bool
+		versions  string
+		dryRun    bool
+		force     bool
+		promptIn  string
+		promptErr error
+		ops       []string
+		out       string
+		err       error
+	}{
+		"secret with force": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd/key",
+			versions: copyVersionsAll,
+			force:    true,
+			ops: []string{
+				"write company/prd/key=old-key",
+				"write company/prd/key=dev-key",
+				"rm company/dev/api_key",
+			},
+			out: "Copy all versions of company/dev/api_key to company/prd/key\n" +
+				"Remove company/dev/api_key\n",
+		},
+		"directory confirmed": {
+			src:       "company/dev/db",
+			dst:       "company/prd/database",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			promptIn:  "db\n",
+			ops: []string{
+				"mkdir company/prd/database",
+				"write company/prd/database/password=dev-password",
+				"write company/prd/database/user=admin",
+				"rmdir company/dev/db",
+			},
+			out: "Create directory company/prd/database\n" +
+				"Copy company/dev/db/password to company/prd/database/password\n" +
+				"Copy company/dev/db/user to company/prd/database/user\n" +
+				"Remove company/dev/db\n",
+		},
+		"name does not match": {
+			src:       "company/dev/db",
+			dst:       "company/prd/database",
+			recursive: true,
+			versions:  copyVersionsAll,
+			promptIn:  "database\n",
+			out:       "Name does not match. Aborting.\n",
+		},
+		"cannot ask": {
+			src:       "company/dev/db",
+			dst:       "company/prd/database",
+			recursive: true,
+			versions:  copyVersionsAll,
+			promptErr: ui.ErrCannotAsk,
+			err:       ErrCannotDoWithoutForce,
+		},
+		"onto existing secret": {
+			src:      "company/dev/db/password",
+			dst:      "company/prd/db",
+			versions: copyVersionsLatest,
+			force:    true,
+			ops: []string{
+				"write company/prd/db/password=dev-password",
+				"rm company/dev/db/password",
+			},
+			out: "Copy company/dev/db/password to company/prd/db/password (overwrites the existing secret)\n" +
+				"Remove company/dev/db/password\n",
+		},
+		"onto existing secret not confirmed": {
+			src:       "company/dev/db",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsLatest,
+			promptIn:  "password\n",
+			out:       "Name does not match. Aborting.\n",
+		},
+		"dry run": {
+			src:      "company/dev/api_key",
+			dst:      "company/prd",
+			versions: copyVersionsAll,
+			dryRun:   true,
+			out: "Copy all versions of company/dev/api_key to company/prd/api_key\n" +
+				"Remove company/dev/api_key\n",
+		},
+		"secret version": {
+			src:      "company/dev/api_key:1",
+			dst:      "company/prd",
+			versions: copyVersionsAll,
+			err:      ErrCannotMoveVersion,
+		},
+		"repository": {
+			src:       "company/dev",
+			dst:       "company/prd",
+			recursive: true,
+			versions:  copyVersionsAll,
+			err:       ErrCannotMoveRootDir,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ops []string
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+			io.PromptErr = tc.promptErr
+			cmd := MvCommand{
+				src:       tc.src,
+				dst:       tc.dst,
+				recursive: tc.recursive,
+				versions:  tc.versions,
+				dryRun:    tc.dryRun,
+				force:     tc.force,
+				io:        io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return newCopyTestClient(&ops), nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, ops, tc.ops)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
@@ -16,6 +13,7 @@ type OrgInspectCommand struct {
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
+	format        string
 }
 
 // NewOrgInspectCommand creates a new OrgInspectCommand.
@@ -32,6 +30,8 @@ func (cmd *OrgInspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Show the details of an organization.")
 	clause.Arg("org-name", "The organization name").Required().SetValue(&cmd.name)
 
+	registerInspectFormatFlag(clause).StringVar(&cmd.format)
+
 	command.BindAction(clause, cmd.Run)
 }
 
@@ -57,14 +57,7 @@ func (cmd *OrgInspectCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newOrgInspectOutput(org, members, repos, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return printStructured(cmd.io.Output(), cmd.format, newOrgInspectOutput(org, members, repos, cmd.timeFormatter))
 }
 
 // OrgInspectOutput is the json format to print out with all the details of an organization.
@@ -3,7 +3,7 @@ package secrethub
 import (
 	"fmt"
 	"sort"
-	"text/tabwriter"
+	"strconv"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -15,6 +15,7 @@ import (
 type OrgLsCommand struct {
 	quiet         bool
 	useTimestamps bool
+	format        string
 	io            ui.IO
 	newClient     newClientFunc
 	timeFormatter TimeFormatter
@@ -34,6 +35,7 @@ func (cmd *OrgLsCommand) Register(r command.Registerer) {
 	clause.Alias("list")
 	clause.Flag("quiet", "Only print organization names.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFormatFlag(clause, orgItem{}).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -46,7 +48,7 @@ func (cmd *OrgLsCommand) Run() error {
 
 // beforeRun configures the command using the flag values.
 func (cmd *OrgLsCommand) beforeRun() {
-	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format))
 }
 
 // Run lists all organizations a user is a member of.
@@ -68,9 +70,10 @@ func (cmd *OrgLsCommand) run() error {
 			fmt.Fprintf(cmd.io.Output(), "%s\n", org.Name)
 		}
 	} else {
-		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", "NAME", "REPOS", "USERS", "CREATED")
+		formatter, err := newListFormatter(cmd.format, cmd.io.Output(), 2, []string{"name", "repos", "users", "created"})
+		if err != nil {
+			return err
+		}
 
 		for _, org := range resp {
 			// TODO SHDEV-724: refactor these two calls to include the counts in the api.Org response by default.
@@ -84,10 +87,19 @@ func (cmd *OrgLsCommand) run() error {
 				return err
 			}
 
-			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", org.Name, len(repos), len(members), cmd.timeFormatter.Format(org.CreatedAt.Local()))
+			item := orgItem{
+				Name:    org.Name,
+				Repos:   len(repos),
+				Users:   len(members),
+				Created: cmd.timeFormatter.Format(org.CreatedAt.Local()),
+			}
+			err = formatter.Write([]string{item.Name, strconv.Itoa(item.Repos), strconv.Itoa(item.Users), item.Created}, item)
+			if err != nil {
+				return err
+			}
 		}
 
-		err = w.Flush()
+		err = formatter.Flush()
 		if err != nil {
 			return err
 		}
@@ -95,3 +107,11 @@ func (cmd *OrgLsCommand) run() error {
 
 	return nil
 }
+
+// orgItem is the json and yaml format of an organization listed by org ls.
+type orgItem struct {
+	Name    string `json:"Name"`
+	Repos   int    `json:"Repos"`
+	Users   int    `json:"Users"`
+	Created string `json:"Created"`
+}
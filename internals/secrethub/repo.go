@@ -29,6 +29,7 @@ func (cmd *RepoCommand) Register(r command.Registerer) {
 	NewRepoInspectCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoInviteCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoExportCommand(cmd.io, cmd.newClient).Register(clause)
+	NewRepoImportCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoLSCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoRevokeCommand(cmd.io, cmd.newClient).Register(clause)
 	NewRepoRmCommand(cmd.io, cmd.newClient).Register(clause)
@@ -2,7 +2,10 @@ package secrethub
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"time"
@@ -12,19 +15,33 @@ import (
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
 	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
 )
 
 // Error
 var (
-	ErrExportAlreadyExists = errMain.Code("export_file_already_exists").Error("the export file already exists")
+	ErrExportAlreadyExists   = errMain.Code("export_file_already_exists").Error("the export file already exists")
+	ErrEmptyExportPassphrase = errMain.Code("empty_export_passphrase").Error("the passphrase to encrypt the export file with cannot be empty")
+)
+
+const (
+	// exportArchiveComment is the comment of export archives in which every entry
+	// to which a trailing newline was added is marked with exportNewLineComment.
+	exportArchiveComment = "secrethub repo export"
+	// exportNewLineComment is the comment of an archive entry to which the export added a trailing newline.
+	exportNewLineComment = "newline added"
 )
 
 // RepoExportCommand exports a repo to a zip file.
 type RepoExportCommand struct {
-	path      api.RepoPath
-	zipName   string
-	io        ui.IO
-	newClient newClientFunc
+	path       api.RepoPath
+	zipName    string
+	encrypt    bool
+	passphrase string
+	// passphraseSet is true when --passphrase is passed, also when its value is empty.
+	passphraseSet bool
+	io            ui.IO
+	newClient     newClientFunc
 }
 
 // NewRepoExportCommand creates a new RepoExportCommand.
@@ -40,15 +57,27 @@ func (cmd *RepoExportCommand) Register(r command.Registerer) {
 	clause := r.Command("export", "Export the repository to a zip file.")
 	clause.Arg("repo-path", "The repository to export").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
 	clause.Arg("zip-file-name", "The file name to assign to the exported .zip file. Defaults to secrethub_export_<namespace>_<repo>_<timestamp>.zip with the timestamp formatted as YYYYMMDD_HHMMSS").StringVar(&cmd.zipName)
+	clause.Flag("encrypt", "Encrypt the exported .zip file with a passphrase. The encrypted file can be imported with the repo import command.").BoolVar(&cmd.encrypt)
+	clause.Flag("passphrase", "The passphrase to encrypt the export file with. When set, it will not prompt for the passphrase. Please only use this if you know what you're doing and ensure your passphrase doesn't end up in bash history.").IsSetByUser(&cmd.passphraseSet).StringVar(&cmd.passphrase)
 
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run exports a repo to a zip file
 func (cmd *RepoExportCommand) Run() error {
+	if cmd.passphraseSet && cmd.passphrase == "" {
+		return ErrEmptyExportPassphrase
+	}
+	if cmd.passphrase != "" {
+		cmd.encrypt = true
+	}
+
 	if cmd.zipName == "" {
 		// secrethub_export_repo_date_time.zip
 		cmd.zipName = fmt.Sprintf("%s_export_%s_%s.zip", ApplicationName, cmd.path.GetRepo(), time.Now().Format("20060102_150405"))
+		if cmd.encrypt {
+			cmd.zipName += ".enc"
+		}
 	}
 
 	_, err := os.Stat(cmd.zipName)
@@ -56,16 +85,22 @@ func (cmd *RepoExportCommand) Run() error {
 		return ErrExportAlreadyExists
 	}
 
-	confirmed, err := ui.ConfirmCaseInsensitive(
-		cmd.io,
-		fmt.Sprintf(
-			"[DANGER ZONE] This will export all the secrets unencrypted in the %s repository. "+
-				"You are responsible for the protection of these secrets. "+
-				"Please type in the full path of the repository to confirm",
-			cmd.path.String(),
-		),
+	question := fmt.Sprintf(
+		"[DANGER ZONE] This will export all the secrets unencrypted in the %s repository. "+
+			"You are responsible for the protection of these secrets. "+
+			"Please type in the full path of the repository to confirm",
 		cmd.path.String(),
 	)
+	if cmd.encrypt {
+		question = fmt.Sprintf(
+			"This will export all the secrets in the %s repository, encrypted with a passphrase. "+
+				"Anyone who knows the passphrase can read these secrets. "+
+				"Please type in the full path of the repository to confirm",
+			cmd.path.String(),
+		)
+	}
+
+	confirmed, err := ui.ConfirmCaseInsensitive(cmd.io, question, cmd.path.String())
 	if err != nil {
 		return err
 	}
@@ -75,34 +110,69 @@ func (cmd *RepoExportCommand) Run() error {
 		return nil
 	}
 
+	passphrase := cmd.passphrase
+	if cmd.encrypt && passphrase == "" {
+		passphrase, err = ui.AskPassphrase(cmd.io, "Please enter a passphrase to encrypt the export file with: ", "Enter the same passphrase again: ", 3)
+		if err != nil {
+			return err
+		}
+		// AskPassphrase returns an empty passphrase as soon as an empty answer is given.
+		if passphrase == "" {
+			return ErrEmptyExportPassphrase
+		}
+	}
+
 	client, err := cmd.newClient()
 	if err != nil {
 		return err
 	}
 
-	rootDir, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
-	if err != nil {
-		return err
+	if cmd.encrypt {
+		buf := &bytes.Buffer{}
+		err = cmd.writeZip(client, buf)
+		if err != nil {
+			return err
+		}
+
+		encrypted, err := encryptExport(buf.Bytes(), []byte(passphrase))
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(cmd.zipName, encrypted, 0600)
+		if err != nil {
+			return ErrCannotWrite(cmd.zipName, err)
+		}
+		return nil
 	}
 
 	zipFile, err := os.Create(cmd.zipName)
 	if err != nil {
 		return err
 	}
-
-	writer := zip.NewWriter(zipFile)
 	defer func() {
-		err := writer.Close()
-		if err != nil {
-			panic(fmt.Errorf("could not close zip file: %s", err))
-		}
-
-		err = zipFile.Close()
+		err := zipFile.Close()
 		if err != nil {
 			panic(fmt.Errorf("could not close zip file: %s", err))
 		}
 	}()
 
+	return cmd.writeZip(client, zipFile)
+}
+
+// writeZip writes all versions of all secrets in the repo as a zip file to the given writer.
+func (cmd *RepoExportCommand) writeZip(client secrethub.ClientInterface, w io.Writer) error {
+	rootDir, err := client.Dirs().GetTree(cmd.path.GetDirPath().Value(), -1, false)
+	if err != nil {
+		return err
+	}
+
+	writer := zip.NewWriter(w)
+	err = writer.SetComment(exportArchiveComment)
+	if err != nil {
+		return err
+	}
+
 	for _, secret := range rootDir.Secrets {
 		secretPath, err := rootDir.AbsSecretPath(secret.SecretID)
 		if err != nil {
@@ -125,17 +195,27 @@ func (cmd *RepoExportCommand) Run() error {
 			// Remove the repo path from the zipfile.
 			zipSecretPath = strings.TrimPrefix(zipSecretPath, versionPath.GetRepoPath().String()+"/")
 
-			zipNode, err := writer.Create(zipSecretPath)
+			data := posix.AddNewLine(version.Data)
+			header := &zip.FileHeader{
+				Name:   zipSecretPath,
+				Method: zip.Deflate,
+			}
+			// Mark the entry, so that the import only removes newlines that are not part of the secret.
+			if len(data) > len(version.Data) {
+				header.Comment = exportNewLineComment
+			}
+
+			zipNode, err := writer.CreateHeader(header)
 			if err != nil {
 				return err
 			}
 
-			_, err = zipNode.Write(posix.AddNewLine(version.Data))
+			_, err = zipNode.Write(data)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	return nil
+	return writer.Close()
 }
@@ -0,0 +1,78 @@
+package secrethub
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRepoExportCommand_Run_Encrypt(t *testing.T) {
+	cases := map[string]struct {
+		passphrase     string
+		passphraseSet  bool
+		promptIn       string
+		expectedExport bool
+		err            error
+	}{
+		"passphrase flag": {
+			passphrase:     "passphrase",
+			passphraseSet:  true,
+			promptIn:       "namespace/repo\n",
+			expectedExport: true,
+		},
+		"empty passphrase flag": {
+			passphraseSet: true,
+			err:           ErrEmptyExportPassphrase,
+		},
+		"empty passphrase answer": {
+			promptIn: "namespace/repo\n",
+			err:      ErrEmptyExportPassphrase,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			io := fakeui.NewIO(t)
+			io.PromptIn.Buffer = bytes.NewBufferString(tc.promptIn)
+
+			cmd := RepoExportCommand{
+				path:          "namespace/repo",
+				zipName:       filepath.Join(dir, "export.zip.enc"),
+				encrypt:       true,
+				passphrase:    tc.passphrase,
+				passphraseSet: tc.passphraseSet,
+				io:            io,
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						DirService: &fakeclient.DirService{
+							GetTreeFunc: func(path string, depth int, ancestors bool) (*api.Tree, error) {
+								return &api.Tree{}, nil
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			exported, err := ioutil.ReadFile(cmd.zipName)
+			assert.Equal(t, err == nil, tc.expectedExport)
+			if tc.expectedExport {
+				_, err = decryptExport(exported, []byte(tc.passphrase))
+				assert.OK(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package secrethub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+)
+
+// Errors
+var (
+	ErrInvalidConflictPolicy = errMain.Code("invalid_conflict_policy").ErrorPref("invalid conflict policy: %s. Options are skip, overwrite and new-version")
+	ErrInvalidExportEntry    = errMain.Code("invalid_export_entry").ErrorPref("the export file contains an invalid entry %s: expected <path>/<secret>/<version>")
+)
+
+const (
+	conflictPolicySkip       = "skip"
+	conflictPolicyOverwrite  = "overwrite"
+	conflictPolicyNewVersion = "new-version"
+)
+
+// RepoImportCommand imports a zip file created with the repo export command into a repo.
+type RepoImportCommand struct {
+	path           api.RepoPath
+	zipFile        string
+	dryRun         bool
+	latestOnly     bool
+	conflictPolicy string
+	force          bool
+	passphrase     string
+	io             ui.IO
+	newClient      newClientFunc
+}
+
+// NewRepoImportCommand creates a new RepoImportCommand.
+func NewRepoImportCommand(io ui.IO, newClient newClientFunc) *RepoImportCommand {
+	return &RepoImportCommand{
+		io:        io,
+		newClient: newClient,
+	}
+}
+
+// Register registers the command, arguments and flags on the provided Registerer.
+func (cmd *RepoImportCommand) Register(r command.Registerer) {
+	clause := r.Command("import", "Import secrets from a zip file created with the repo export command into a repository.")
+	clause.Arg("repo-path", "The repository to import the secrets into").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
+	clause.Arg("zip-file", "The path to the exported .zip file. Encrypted export files are decrypted with a passphrase").Required().StringVar(&cmd.zipFile)
+	clause.Flag("dry-run", "Only print the directories and secrets that would be created, without making any changes.").BoolVar(&cmd.dryRun)
+	clause.Flag("latest-only", "Only import the latest version of every secret instead of replaying all versions.").BoolVar(&cmd.latestOnly)
+	clause.Flag("on-conflict", "What to do with secrets that already exist in the repository. Options are `skip`, `overwrite` (remove the existing secret and all its versions first) and `new-version` (add the imported versions on top of the existing ones). Defaults to skip.").Default(conflictPolicySkip).HintOptions(conflictPolicySkip, conflictPolicyOverwrite, conflictPolicyNewVersion).StringVar(&cmd.conflictPolicy)
+	clause.Flag("passphrase", "The passphrase to decrypt an encrypted export file with. When set, it will not prompt for the passphrase. Please only use this if you know what you're doing and ensure your passphrase doesn't end up in bash history.").StringVar(&cmd.passphrase)
+	registerForceFlag(clause).BoolVar(&cmd.force)
+
+	command.BindAction(clause, cmd.Run)
+}
+
+// Run imports the secrets in the zip file into the repo.
+func (cmd *RepoImportCommand) Run() error {
+	switch cmd.conflictPolicy {
+	case conflictPolicySkip, conflictPolicyOverwrite, conflictPolicyNewVersion:
+	default:
+		return ErrInvalidConflictPolicy(cmd.conflictPolicy)
+	}
+
+	raw, err := ioutil.ReadFile(cmd.zipFile)
+	if err != nil {
+		return ErrReadFile(cmd.zipFile, err)
+	}
+
+	if isEncryptedExport(raw) {
+		passphrase := cmd.passphrase
+		if passphrase == "" {
+			passphrase, err = ui.AskSecret(cmd.io, "Please enter the passphrase to decrypt the export file: ")
+			if err != nil {
+				return err
+			}
+		}
+
+		raw, err = decryptExport(raw, []byte(passphrase))
+		if err != nil {
+			return err
+		}
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return ErrReadFile(cmd.zipFile, err)
+	}
+
+	secrets, err := readExportArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	return cmd.importSecrets(secrets)
+}
+
+// importSecrets recreates the given exported secrets in the repository.
+func (cmd *RepoImportCommand) importSecrets(secrets []exportedSecret) error {
+	if cmd.conflictPolicy == conflictPolicyOverwrite && !cmd.dryRun {
+		ok, err := askRmConfirmation(
+			cmd.io,
+			fmt.Sprintf("This will permanently remove all secrets in the %s repository that also exist in the export file. "+
+				"Please type in the full path of the repository to confirm", cmd.path.String()),
+			cmd.force,
+			cmd.path.String(),
+		)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range exportedDirs(secrets) {
+		dirPath := cmd.path.String() + "/" + dir
+		exists, err := client.Dirs().Exists(dirPath)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		if cmd.dryRun {
+			fmt.Fprintf(cmd.io.Output(), "Would create directory %s\n", dirPath)
+			continue
+		}
+
+		_, err = client.Dirs().Create(dirPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.io.Output(), "Created directory %s\n", dirPath)
+	}
+
+	imported := 0
+	for _, secret := range secrets {
+		secretPath := cmd.path.String() + "/" + secret.path
+
+		versions := secret.versions
+		if cmd.latestOnly {
+			versions = versions[len(versions)-1:]
+		}
+
+		ok, err := cmd.resolveConflict(client, secretPath)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if cmd.dryRun {
+			fmt.Fprintf(cmd.io.Output(), "Would write %s to %s\n", pluralize("version", "versions", len(versions)), secretPath)
+			continue
+		}
+
+		for _, version := range versions {
+			_, err = client.Secrets().Write(secretPath, version.data)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.io.Output(), "Wrote %s to %s\n", pluralize("version", "versions", len(versions)), secretPath)
+		imported++
+	}
+
+	if !cmd.dryRun {
+		fmt.Fprintf(cmd.io.Output(), "Import complete! Imported %s into %s.\n", pluralize("secret", "secrets", imported), cmd.path)
+	}
+
+	return nil
+}
+
+// resolveConflict applies the conflict policy when a secret already exists
+// at the given path and returns whether the versions should be written.
+func (cmd *RepoImportCommand) resolveConflict(client secrethub.ClientInterface, secretPath string) (bool, error) {
+	exists, err := client.Secrets().Exists(secretPath)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, nil
+	}
+
+	switch cmd.conflictPolicy {
+	case conflictPolicyOverwrite:
+		if cmd.dryRun {
+			fmt.Fprintf(cmd.io.Output(), "Would remove existing secret %s\n", secretPath)
+			return true, nil
+		}
+		err = client.Secrets().Delete(secretPath)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	case conflictPolicyNewVersion:
+		return true, nil
+	default:
+		fmt.Fprintf(cmd.io.Output(), "Skipping %s: the secret already exists\n", secretPath)
+		return false, nil
+	}
+}
+
+// exportedSecret is a secret read from an export file.
+type exportedSecret struct {
+	// path is the path of the secret relative to the repository.
+	path     string
+	versions []exportedVersion
+}
+
+// exportedVersion is a single secret version read from an export file.
+type exportedVersion struct {
+	version int
+	data    []byte
+}
+
+// readExportArchive reads all secrets from an archive created with the repo export command.
+// The returned secrets are sorted by path and their versions are sorted in ascending order.
+// Archives created by older versions of the export command do not mark the entries to which
+// a trailing newline was added, so a trailing newline is removed from all their entries.
+func readExportArchive(archive *zip.Reader) ([]exportedSecret, error) {
+	marksNewLines := archive.Comment == exportArchiveComment
+	secretsByPath := make(map[string]*exportedSecret)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		i := strings.LastIndex(file.Name, "/")
+		if i <= 0 {
+			return nil, ErrInvalidExportEntry(file.Name)
+		}
+		secretPath := file.Name[:i]
+		versionNumber, err := strconv.Atoi(file.Name[i+1:])
+		if err != nil {
+			return nil, ErrInvalidExportEntry(file.Name)
+		}
+
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !marksNewLines || file.Comment == exportNewLineComment {
+			data = bytes.TrimSuffix(data, []byte("\n"))
+		}
+
+		secret, ok := secretsByPath[secretPath]
+		if !ok {
+			secret = &exportedSecret{path: secretPath}
+			secretsByPath[secretPath] = secret
+		}
+		secret.versions = append(secret.versions, exportedVersion{
+			version: versionNumber,
+			data:    data,
+		})
+	}
+
+	secrets := make([]exportedSecret, 0, len(secretsByPath))
+	for _, secret := range secretsByPath {
+		sort.Slice(secret.versions, func(i, j int) bool {
+			return secret.versions[i].version < secret.versions[j].version
+		})
+		secrets = append(secrets, *secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].path < secrets[j].path
+	})
+
+	return secrets, nil
+}
+
+// readZipFile returns the contents of a file in a zip archive.
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(io.LimitReader(rc, int64(file.UncompressedSize64)))
+}
+
+// exportedDirs returns the paths of all directories relative to the repository
+// that contain the given secrets, sorted so that parents come before their children.
+func exportedDirs(secrets []exportedSecret) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, secret := range secrets {
+		for dir := path.Dir(secret.path); dir != "."; dir = path.Dir(dir) {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
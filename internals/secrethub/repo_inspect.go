@@ -1,9 +1,6 @@
 package secrethub
 
 import (
-	"fmt"
-
-	"github.com/secrethub/secrethub-cli/internals/cli"
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
 
@@ -14,6 +11,7 @@ import (
 type RepoInspectCommand struct {
 	path          api.RepoPath
 	timeFormatter TimeFormatter
+	format        string
 	io            ui.IO
 	newClient     newClientFunc
 }
@@ -32,6 +30,8 @@ func (cmd *RepoInspectCommand) Register(r command.Registerer) {
 	clause := r.Command("inspect", "Show the details of a repository.")
 	clause.Arg("repo-path", "Path to the repository").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.path)
 
+	registerInspectFormatFlag(clause).StringVar(&cmd.format)
+
 	command.BindAction(clause, cmd.Run)
 }
 
@@ -57,14 +57,7 @@ func (cmd *RepoInspectCommand) Run() error {
 		return err
 	}
 
-	output, err := cli.PrettyJSON(newInspectRepoOutput(repo, users, services, cmd.timeFormatter))
-	if err != nil {
-		return err
-	}
-
-	fmt.Fprintln(cmd.io.Output(), output)
-
-	return nil
+	return printStructured(cmd.io.Output(), cmd.format, newInspectRepoOutput(repo, users, services, cmd.timeFormatter))
 }
 
 func newInspectRepoOutput(repo *api.Repo, users []*api.User, services []*api.Service, timeFormatter TimeFormatter) inspectRepoOutput {
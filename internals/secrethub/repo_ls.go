@@ -3,7 +3,6 @@ package secrethub
 import (
 	"fmt"
 	"sort"
-	"text/tabwriter"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -15,6 +14,7 @@ import (
 type RepoLSCommand struct {
 	useTimestamps bool
 	quiet         bool
+	format        string
 	workspace     api.Namespace
 	io            ui.IO
 	timeFormatter TimeFormatter
@@ -36,6 +36,7 @@ func (cmd *RepoLSCommand) Register(r command.Registerer) {
 	clause.Flag("quiet", "Only print paths.").Short('q').BoolVar(&cmd.quiet)
 	clause.Arg("workspace", "When supplied, results are limited to repositories in this workspace.").SetValue(&cmd.workspace)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	registerOutputFormatFlag(clause, lsItem{}).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -48,7 +49,7 @@ func (cmd *RepoLSCommand) Run() error {
 
 // beforeRun configures the command using the flag values.
 func (cmd *RepoLSCommand) beforeRun() {
-	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps)
+	cmd.timeFormatter = NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format))
 }
 
 // run lists the repositories a user has access to.
@@ -78,12 +79,17 @@ func (cmd *RepoLSCommand) run() error {
 			fmt.Fprintf(cmd.io.Output(), "%s\n", repo.Path())
 		}
 	} else {
-		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
-		fmt.Fprintf(w, "%s\t%s\t%s\n", "NAME", "STATUS", "CREATED")
+		formatter, err := newListFormatter(cmd.format, cmd.io.Output(), 2, lsHeader)
+		if err != nil {
+			return err
+		}
 		for _, repo := range list {
-			fmt.Fprintf(w, "%s\t%s\t%s\n", repo.Path(), repo.Status, cmd.timeFormatter.Format(repo.CreatedAt.Local()))
+			err = writeLsItem(formatter, repo.Path().String(), repo.Status, cmd.timeFormatter.Format(repo.CreatedAt.Local()))
+			if err != nil {
+				return err
+			}
 		}
-		err = w.Flush()
+		err = formatter.Flush()
 		if err != nil {
 			return err
 		}
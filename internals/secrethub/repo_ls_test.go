@@ -53,6 +53,70 @@ func TestRepoLSCommand_run(t *testing.T) {
 				"dev1/repository       ok      2018-01-01T01:01:01+01:00\n" +
 				"dev2/applicationname  ok      2018-01-01T01:01:01+01:00\n",
 		},
+		"success json": {
+			cmd: RepoLSCommand{
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+01:00",
+				},
+				format: formatJSON,
+			},
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{
+							Owner:     "dev1",
+							Name:      "repository",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+					}, nil
+				},
+			},
+			out: "{\"Name\":\"dev1/repository\",\"Status\":\"ok\",\"Created\":\"2018-01-01T01:01:01+01:00\"}\n",
+		},
+		"success yaml": {
+			cmd: RepoLSCommand{
+				timeFormatter: &fakes.TimeFormatter{
+					Response: "2018-01-01T01:01:01+01:00",
+				},
+				format: formatYAML,
+			},
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{
+						{
+							Owner:     "dev1",
+							Name:      "repository",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+						{
+							Owner:     "dev2",
+							Name:      "applicationname",
+							Status:    api.StatusOK,
+							CreatedAt: testTime,
+						},
+					}, nil
+				},
+			},
+			out: "- Name: dev1/repository\n" +
+				"  Status: ok\n" +
+				"  Created: \"2018-01-01T01:01:01+01:00\"\n" +
+				"- Name: dev2/applicationname\n" +
+				"  Status: ok\n" +
+				"  Created: \"2018-01-01T01:01:01+01:00\"\n",
+		},
+		"invalid format": {
+			cmd: RepoLSCommand{
+				format: "xml",
+			},
+			repoService: fakeclient.RepoService{
+				ListMineFunc: func() ([]*api.Repo, error) {
+					return []*api.Repo{}, nil
+				},
+			},
+			err: ErrInvalidOutputFormat("xml"),
+		},
 		"success two repos quiet": {
 			cmd: RepoLSCommand{
 				timeFormatter: &fakes.TimeFormatter{
@@ -2,13 +2,14 @@ package secrethub
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
+	"sort"
 	"strings"
-	"syscall"
+	"time"
 
-	"github.com/secrethub/secrethub-cli/internals/cli/masker"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
@@ -24,6 +25,7 @@ var (
 	errRun                    = errio.Namespace("run")
 	ErrStartFailed            = errRun.Code("start_failed").ErrorPref("error while starting process: %s")
 	ErrSignalFailed           = errRun.Code("signal_failed").ErrorPref("error while propagating signal to process: %s")
+	ErrKillFailed             = errRun.Code("kill_failed").ErrorPref("error while killing process: %s")
 	ErrReadEnvDir             = errRun.Code("env_dir_read_error").ErrorPref("could not read the environment directory: %s")
 	ErrReadEnvFile            = errRun.Code("env_file_read_error").ErrorPref("could not read the environment file %s: %s")
 	ErrReadDefaultEnvFile     = errRun.Code("default_env_file_read_error").ErrorPref("could not read default run env-file %s: %s")
@@ -31,6 +33,9 @@ var (
 	ErrParsingTemplate        = errRun.Code("template_parsing_failed").ErrorPref("error while processing template file '%s': %s")
 	ErrInvalidTemplateVar     = errRun.Code("invalid_template_var").ErrorPref("template variable '%s' is invalid: template variables may only contain uppercase letters, digits, and the '_' (underscore) and are not allowed to start with a number")
 	ErrSecretsNotAllowedInKey = errRun.Code("secret_in_key").Error("secrets are not allowed in run template keys")
+	ErrResolveEnvVar          = errRun.Code("env_var_resolve_failed").ErrorPref("could not resolve %s (%s): %s")
+	ErrEnvFileIncludeCycle    = errRun.Code("env_file_include_cycle").ErrorPref("env files include each other in a cycle: %s")
+	ErrConflictingEnvVar      = errRun.Code("conflicting_env_var").ErrorPref("environment variable %s is defined in both %s and %s: remove one of the definitions or run without --strict-precedence")
 )
 
 const (
@@ -53,18 +58,27 @@ type RunCommand struct {
 	command              []string
 	environment          *environment
 	noMasking            bool
-	maskerOptions        masker.Options
+	masking              maskingFlags
 	newClient            newClientFunc
 	ignoreMissingSecrets bool
+	watchInterval        time.Duration
+	watchSignal          string
+	specFile             string
+	specDir              string
+	files                map[string]string
+	fileTemplates        map[string]string
+	pty                  bool
 }
 
 // NewRunCommand creates a new RunCommand.
 func NewRunCommand(io ui.IO, newClient newClientFunc) *RunCommand {
 	return &RunCommand{
-		io:          io,
-		osEnv:       os.Environ(),
-		environment: newEnvironment(io, newClient),
-		newClient:   newClient,
+		io:            io,
+		osEnv:         os.Environ(),
+		environment:   newEnvironment(io, newClient),
+		newClient:     newClient,
+		files:         make(map[string]string),
+		fileTemplates: make(map[string]string),
 	}
 }
 
@@ -80,127 +94,245 @@ func (cmd *RunCommand) Register(r command.Registerer) {
 	clause.Alias("exec")
 	clause.Arg("command", "The command to execute").Required().StringsVar(&cmd.command)
 	clause.Flag("no-masking", "Disable masking of secrets on stdout and stderr").BoolVar(&cmd.noMasking)
-	clause.Flag("no-output-buffering", "Disable output buffering. This increases output responsiveness, but decreases the probability that secrets get masked.").BoolVar(&cmd.maskerOptions.DisableBuffer)
-	clause.Flag("masking-buffer-period", "The time period for which output is buffered. A higher value increases the probability that secrets get masked but decreases output responsiveness.").Default("50ms").DurationVar(&cmd.maskerOptions.BufferDelay)
+	cmd.masking.register(clause)
 	clause.Flag("ignore-missing-secrets", "Do not return an error when a secret does not exist and use an empty value instead.").BoolVar(&cmd.ignoreMissingSecrets)
+	clause.Flag("watch", "Resolve all secrets again at the given interval, e.g. 5m. When any of the values has changed, the command is restarted with the new values. "+
+		"A restarted command is sent SIGTERM and is killed when it has not exited within "+restartGracePeriod.String()+". "+
+		"Only the environment variables are refreshed: the files written with --file, --file-template and --spec keep the values they had when the command was first started.").DurationVar(&cmd.watchInterval)
+	clause.Flag("watch-signal", "Send this signal to the command instead of restarting it when the secrets change with --watch, e.g. HUP.").StringVar(&cmd.watchSignal)
+	clause.Flag("spec", "The path to a secrets.yml spec file. Its env consumables are passed as environment variables and its file and inject consumables are written to a private temporary directory, "+
+		"of which the path is passed in the "+specDirEnvVar+" environment variable. The directory is removed when the command exits.").ExistingFileVar(&cmd.specFile)
+	clause.Flag("file", "Write a secret to a file that only exists while the command runs and set an environment variable to the path of the file with `NAME=<path>`. "+
+		"This is useful for tools that only read credentials from files. On Linux the file is kept in memory, on other platforms it is written to a private temporary directory.").StringMapVar(&cmd.files)
+	clause.Flag("file-template", "Inject the secrets in a template and write the result to a file that only exists while the command runs, setting an environment variable to the path of the file with `NAME=<template path>`.").StringMapVar(&cmd.fileTemplates)
+	clause.Flag("pty", "Run the command in a pseudo-terminal, so that interactive programs keep their colors, line editing and window size handling, while their output is still masked. "+
+		"The stdout and stderr of the command are both written to stdout. Only supported on Linux.").BoolVar(&cmd.pty)
 	cmd.environment.register(clause)
 	command.BindAction(clause, cmd.Run)
 }
 
 // Run reads files from the .secretsenv/<env-name> directory, sets them as environment variables and runs the given command.
 // Note that the environment variables are only passed to the child process and not exported globally, which is nice.
+// When the command does not exit successfully, an *ExitStatusError is returned.
+// All files containing secrets are removed before it returns, also when the command is stopped by a signal.
 func (cmd *RunCommand) Run() error {
+	if cmd.noMasking && (cmd.masking.report != "" || cmd.masking.failOnLeak) {
+		return ErrMaskingDisabled
+	}
+
+	maskValues, err := cmd.masking.configure()
+	if err != nil {
+		return err
+	}
+
+	spec := &runSpec{}
+	if cmd.specFile != "" {
+		spec, err = parseSpec(cmd.specFile)
+		if err != nil {
+			return err
+		}
+		defer spec.cleanup()
+
+		cmd.specDir = spec.dir
+		cmd.environment.specFile = cmd.specFile
+		cmd.environment.specEnv = spec.env
+	}
+
 	environment, secrets, err := cmd.sourceEnvironment()
 	if err != nil {
 		return err
 	}
 
+	// Signals are caught before any secrets are written to files, so that the files are removed when a signal
+	// is received. A terminating signal received before the command is started aborts the run. Once the command
+	// is started, signals are forwarded to it and the files are removed after it exits.
+	// The channel is buffered, so that signals sent in quick succession are not dropped while one is forwarded.
+	signals := make(chan os.Signal, signalBufferSize)
+	signal.Notify(signals)
+	defer signal.Stop(signals)
+
+	err = spec.write(cmd.masking.secretReader(cmd.newClient))
+	if err != nil {
+		return err
+	}
+
+	files := &secretFiles{}
+	defer files.cleanup()
+	fileSecrets, err := cmd.writeSecretFiles(files)
+	if err != nil {
+		return err
+	}
+
 	// This makes sure commands encapsulated in quotes also work.
 	if len(cmd.command) == 1 {
 		cmd.command = strings.Split(cmd.command[0], " ")
 	}
 
-	sequences := make([][]byte, 0, len(secrets))
-	for _, val := range secrets {
-		if val != "" {
-			sequences = append(sequences, []byte(val))
+	var watchSignal os.Signal
+	if cmd.watchSignal != "" {
+		watchSignal, err = parseSignal(cmd.watchSignal)
+		if err != nil {
+			return err
 		}
 	}
-	m := masker.New(sequences, &cmd.maskerOptions)
 
-	command := exec.Command(cmd.command[0], cmd.command[1:]...)
-	command.Env = environment
-	command.Stdin = os.Stdin
-	if cmd.noMasking {
-		command.Stdout = cmd.io.Stdout()
-		command.Stderr = os.Stderr
-	} else {
-		command.Stdout = m.AddStream(cmd.io.Stdout())
-		command.Stderr = m.AddStream(os.Stderr)
+	maskedSecrets := append(secrets, spec.secrets...)
+	maskedSecrets = append(maskedSecrets, fileSecrets...)
+	m := cmd.masking.newMasker(maskedSecrets, maskValues)
+
+	stdout := io.Writer(cmd.io.Stdout())
+	stderr := io.Writer(os.Stderr)
+	if !cmd.noMasking {
+		stdout = m.AddNamedStream("stdout", stdout)
+		stderr = m.AddNamedStream("stderr", stderr)
 
 		go m.Start()
 	}
 
-	err = command.Start()
-	if err != nil {
-		return ErrStartFailed(err)
+	var pty *pseudoTerminal
+	if cmd.pty {
+		pty, err = openPseudoTerminal(os.Stdin, stdout)
+		if err != nil {
+			return err
+		}
 	}
 
-	done := make(chan bool, 1)
+	done := make(chan struct{})
+	defer close(done)
 
-	// Pass all signals to child process
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals)
+	var changes <-chan environmentChange
+	if cmd.watchInterval > 0 {
+		changes = newEnvironmentWatcher(cmd.watchInterval, environment, cmd.sourceEnvironment, os.Stderr).watch(done)
+	}
 
-	go func() {
-		select {
-		case s := <-signals:
-			err := command.Process.Signal(s)
-			if err != nil && !strings.Contains(err.Error(), "process already finished") {
-				fmt.Fprintln(os.Stderr, ErrSignalFailed(err))
+	s := &supervisor{
+		start: func(env []string) (process, error) {
+			command := exec.Command(cmd.command[0], cmd.command[1:]...)
+			if pty != nil {
+				pty.attach(command)
+			} else {
+				command.Stdin = os.Stdin
+				command.Stdout = stdout
+				command.Stderr = stderr
 			}
-		case <-done:
-			signal.Stop(signals)
-			return
-		}
-	}()
+			command.Env = append(env, files.env()...)
+			command.ExtraFiles = files.extraFiles
+			return startProcess(command)
+		},
+		signals: signals,
+		handleSignal: func(sig os.Signal) bool {
+			if pty == nil || !isWindowChangeSignal(sig) {
+				return false
+			}
+			err := pty.resize()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not resize the pseudo-terminal: %s\n", err)
+			}
+			return true
+		},
+		changes: changes,
+		onChange: func(change environmentChange) {
+			cmd.masking.maskSecrets(m, change.secrets)
+		},
+		watchSignal: watchSignal,
+		gracePeriod: restartGracePeriod,
+		stderr:      os.Stderr,
+	}
+	commandErr := s.run(environment)
 
-	commandErr := command.Wait()
-	done <- true
+	// The pseudo-terminal is closed before the masker is stopped, so that all output of the command is masked.
+	// The masker is stopped also when closing the pseudo-terminal fails, so that no masked output is lost.
+	var ptyErr error
+	if pty != nil {
+		ptyErr = pty.close()
+	}
 
 	if !cmd.noMasking {
 		err := m.Stop()
 		if err != nil {
 			return err
 		}
+
+		// The masks are reported, also when the command failed, because its output may have contained secrets.
+		err = cmd.masking.reportMasks(m.Report())
+		if err != nil && commandErr == nil {
+			return err
+		}
 	}
 
-	if commandErr != nil {
-		// Check if the program exited with an error
-		exitErr, ok := commandErr.(*exec.ExitError)
-		if ok {
-			waitStatus, ok := exitErr.Sys().(syscall.WaitStatus)
-			if ok {
-				// Return the status code returned by the process
-				os.Exit(waitStatus.ExitStatus())
-				return nil
-			}
+	if ptyErr != nil {
+		return ptyErr
+	}
+	return commandErr
+}
 
+// maskSequences returns the sequences that should be masked for the given secret values.
+func maskSequences(secrets []string) [][]byte {
+	sequences := make([][]byte, 0, len(secrets))
+	for _, val := range secrets {
+		if val != "" {
+			sequences = append(sequences, []byte(val))
 		}
-		return commandErr
 	}
-
-	return nil
+	return sequences
 }
 
 // sourceEnvironment returns the environment of the subcommand, with all the secrets sourced
 // and the secret values that need to be masked.
 func (cmd *RunCommand) sourceEnvironment() ([]string, []string, error) {
 	_, passthroughEnv := parseKeyValueStringsToMap(cmd.osEnv)
-	newEnv := map[string]string{}
 
 	envValues, err := cmd.environment.env()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var sr tpl.SecretReader = newSecretReader(cmd.newClient)
-	if cmd.ignoreMissingSecrets {
+	newEnv, secrets, err := resolveEnv(envValues, cmd.masking.secretReader(cmd.newClient), cmd.ignoreMissingSecrets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cmd.specDir != "" {
+		newEnv[specDirEnvVar] = cmd.specDir
+	}
+
+	// Finally add the unparsed variables
+	processedOsEnv := append(passthroughEnv, mapToKeyValueStrings(newEnv)...)
+
+	return processedOsEnv, secrets, nil
+}
+
+// resolveEnv resolves all environment values with the secret reader. It returns the resolved
+// environment and the values of all secrets that were read.
+func resolveEnv(envValues map[string]value, sr tpl.SecretReader, ignoreMissingSecrets bool) (map[string]string, []string, error) {
+	// Resolve the values in a fixed order, so that the same error is reported on every run.
+	names := make([]string, 0, len(envValues))
+	for name := range envValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sr = prefetchSecrets(sr, func(sr tpl.SecretReader) {
+		for _, name := range names {
+			// Errors are returned when the values are resolved with the prefetched secrets below.
+			_, _ = envValues[name].resolve(sr)
+		}
+	})
+	if ignoreMissingSecrets {
 		sr = newIgnoreMissingSecretReader(sr)
 	}
 	secretReader := newBufferedSecretReader(sr)
 
-	for name, value := range envValues {
-		newEnv[name], err = value.resolve(secretReader)
+	env := make(map[string]string, len(names))
+	for _, name := range names {
+		var err error
+		env[name], err = envValues[name].resolve(secretReader)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
 
-	// Finally add the unparsed variables
-	processedOsEnv := append(passthroughEnv, mapToKeyValueStrings(newEnv)...)
-
-	return processedOsEnv, secretReader.Values(), nil
+	return env, secretReader.Values(), nil
 }
 
 // mapToKeyValueStrings converts a map to a slice of key=value pairs.
@@ -0,0 +1,172 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/validation"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrDuplicateFileEnvVar    = errRun.Code("duplicate_file_env_var").ErrorPref("environment variable %s is set with both --file and --file-template")
+	ErrCannotWriteSecretFile  = errRun.Code("cannot_write_secret_file").ErrorPref("cannot write the file for environment variable %s: %s")
+	ErrCannotCreateSecretsDir = errRun.Code("cannot_create_secrets_dir").ErrorPref("cannot create a directory for the secret files: %s")
+)
+
+const (
+	secretFilesDirPrefix = "secrethub-files-"
+	secretFileMode       = 0600
+)
+
+// writeSecretFiles writes the secrets of the --file and --file-template flags to the given files.
+// It returns the values of the secrets written, which should be masked.
+func (cmd *RunCommand) writeSecretFiles(files *secretFiles) ([]string, error) {
+	if len(cmd.files) == 0 && len(cmd.fileTemplates) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]value, len(cmd.files)+len(cmd.fileTemplates))
+	for name, path := range cmd.files {
+		err := validation.ValidateEnvarName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = api.ValidateSecretPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		values[name] = &sourcedValue{
+			value:  newSecretValue(path),
+			name:   name,
+			origin: provenance{source: "--file"},
+		}
+	}
+
+	if len(cmd.fileTemplates) > 0 {
+		varReader, err := cmd.environment.variableReader()
+		if err != nil {
+			return nil, err
+		}
+
+		for name, templatePath := range cmd.fileTemplates {
+			err := validation.ValidateEnvarName(name)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, exists := values[name]; exists {
+				return nil, ErrDuplicateFileEnvVar(name)
+			}
+
+			raw, err := ioutil.ReadFile(templatePath)
+			if err != nil {
+				return nil, ErrCannotReadFile(templatePath, err)
+			}
+
+			template, err := parseTemplate(raw, cmd.environment.templateVersion)
+			if err != nil {
+				return nil, ErrParsingTemplate(templatePath, err)
+			}
+
+			values[name] = &sourcedValue{
+				value:  newTemplateValue(templatePath, 0, template, varReader),
+				name:   name,
+				origin: provenance{source: "--file-template " + templatePath},
+			}
+		}
+	}
+
+	contents, secrets, err := resolveEnv(values, cmd.masking.secretReader(cmd.newClient), cmd.ignoreMissingSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the files in a fixed order, so that they get the same file descriptors on every run.
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		err = files.write(name, []byte(contents[name]))
+		if err != nil {
+			return nil, ErrCannotWriteSecretFile(name, err)
+		}
+	}
+	return secrets, nil
+}
+
+// secretFiles contains the files with secrets that only exist while the command runs.
+// Where supported, the files are kept in memory and passed to the command as file
+// descriptors. Otherwise, they are written to a private temporary directory.
+type secretFiles struct {
+	// paths maps the environment variables to the paths of their files.
+	paths map[string]string
+	// extraFiles are the in-memory files that are inherited by the command.
+	extraFiles []*os.File
+	// dir is the private directory for files that are written to disk.
+	dir string
+}
+
+// write writes the data to a new file of which the path is set in the environment variable.
+func (f *secretFiles) write(name string, data []byte) error {
+	if f.paths == nil {
+		f.paths = make(map[string]string)
+	}
+
+	file, err := createMemFile(name, data)
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		f.extraFiles = append(f.extraFiles, file)
+		// The extra files are inherited by the command as file descriptors 3 and up.
+		f.paths[name] = fmt.Sprintf("/dev/fd/%d", 2+len(f.extraFiles))
+		return nil
+	}
+
+	if f.dir == "" {
+		// ioutil.TempDir creates the directory with 0700 permissions, so only the current user can read the files.
+		f.dir, err = ioutil.TempDir("", secretFilesDirPrefix)
+		if err != nil {
+			return ErrCannotCreateSecretsDir(err)
+		}
+	}
+
+	path := filepath.Join(f.dir, name)
+	err = ioutil.WriteFile(path, data, secretFileMode)
+	if err != nil {
+		return err
+	}
+	f.paths[name] = path
+	return nil
+}
+
+// env returns the environment variables that contain the paths of the files.
+func (f *secretFiles) env() []string {
+	return mapToKeyValueStrings(f.paths)
+}
+
+// cleanup closes the in-memory files and removes the files written to disk.
+func (f *secretFiles) cleanup() {
+	for _, file := range f.extraFiles {
+		_ = file.Close()
+	}
+	f.extraFiles = nil
+
+	if f.dir != "" {
+		err := os.RemoveAll(f.dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not remove the directory %s containing secret files: %s\n", f.dir, err)
+		}
+	}
+}
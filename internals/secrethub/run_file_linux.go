@@ -0,0 +1,46 @@
+// +build linux
+
+package secrethub
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// createMemFile creates an anonymous file that only exists in memory and writes the data to it.
+// If the kernel does not support in-memory files, nil is returned.
+func createMemFile(name string, data []byte) (*os.File, error) {
+	fd, err := unix.MemfdCreate(name, unix.MFD_CLOEXEC)
+	if err == unix.ENOSYS || err == unix.EPERM {
+		// memfd_create is not available before Linux 3.17 and can be blocked by a seccomp profile.
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	file := os.NewFile(uintptr(fd), name)
+	err = writeMemFile(file, data)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// writeMemFile writes the data to the in-memory file, only readable by the current user,
+// and rewinds it so that the command can read it from the start.
+func writeMemFile(file *os.File, data []byte) error {
+	err := file.Chmod(secretFileMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Seek(0, 0)
+	return err
+}
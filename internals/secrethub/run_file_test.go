@@ -0,0 +1,138 @@
+package secrethub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRunCommand_Run_SecretFiles(t *testing.T) {
+	cases := map[string]struct {
+		files         map[string]string
+		fileTemplates map[string]string
+		command       string
+		out           string
+		err           error
+	}{
+		"file": {
+			files: map[string]string{
+				"CERT": "company/app/tls.crt",
+			},
+			command: `cat "$CERT"`,
+			out:     "value of company/app/tls.crt",
+		},
+		"file template": {
+			fileTemplates: map[string]string{
+				"CONFIG": "config.tpl",
+			},
+			command: `cat "$CONFIG"`,
+			out:     "user: value of company/app/db/user\n",
+		},
+		"multiple files": {
+			files: map[string]string{
+				"CERT": "company/app/tls.crt",
+				"KEY":  "company/app/tls.key",
+			},
+			command: `cat "$KEY" "$CERT"`,
+			out:     "value of company/app/tls.keyvalue of company/app/tls.crt",
+		},
+		"exit status": {
+			files: map[string]string{
+				"CERT": "company/app/tls.crt",
+			},
+			command: `test -r "$CERT" && exit 3`,
+			err:     &ExitStatusError{status: 3},
+		},
+		"same variable as file and template": {
+			files: map[string]string{
+				"CONFIG": "company/app/config",
+			},
+			fileTemplates: map[string]string{
+				"CONFIG": "config.tpl",
+			},
+			err: ErrDuplicateFileEnvVar("CONFIG"),
+		},
+		"secret not found": {
+			files: map[string]string{
+				"CERT": "company/app/missing",
+			},
+			err: ErrResolveEnvVar("CERT", provenance{source: "--file"}, api.ErrSecretNotFound),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			writeTestFiles(t, dir, map[string]string{"config.tpl": "user: {{ company/app/db/user }}\n"})
+			fileTemplates := make(map[string]string, len(tc.fileTemplates))
+			for name, path := range tc.fileTemplates {
+				fileTemplates[name] = filepath.Join(dir, path)
+			}
+
+			io := fakeui.NewIO(t)
+			cmd := RunCommand{
+				io:            io,
+				command:       []string{"/bin/sh", "-c", tc.command},
+				noMasking:     true,
+				files:         tc.files,
+				fileTemplates: fileTemplates,
+				environment: &environment{
+					osStat:                       osStatFunc("secrethub.env", os.ErrNotExist),
+					templateVersion:              "auto",
+					dontPromptMissingTemplateVar: true,
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									if path == "company/app/missing" {
+										return nil, api.ErrSecretNotFound
+									}
+									return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			out, err := io.ReadStdout()
+			assert.OK(t, err)
+			assert.Equal(t, string(out), tc.out)
+		})
+	}
+}
+
+func TestSecretFiles_cleanup(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	tmpDir := os.Getenv("TMPDIR")
+	defer os.Setenv("TMPDIR", tmpDir)
+	err := os.Setenv("TMPDIR", dir)
+	assert.OK(t, err)
+
+	files := &secretFiles{}
+	err = files.write("CERT", []byte("secret"))
+	assert.OK(t, err)
+
+	files.cleanup()
+
+	assert.Equal(t, len(files.extraFiles), 0)
+	written, err := filepath.Glob(filepath.Join(dir, secretFilesDirPrefix+"*"))
+	assert.OK(t, err)
+	assert.Equal(t, len(written), 0)
+}
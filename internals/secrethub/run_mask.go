@@ -0,0 +1,302 @@
+package secrethub
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/secrethub/secrethub-cli/internals/cli"
+	"github.com/secrethub/secrethub-cli/internals/cli/masker"
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrUnknownMaskEncoding = errRun.Code("unknown_mask_encoding").ErrorPref("unknown mask encoding %s: supported encodings are %s")
+	ErrInvalidMaskRegex    = errRun.Code("invalid_mask_regex").ErrorPref("invalid regular expression %s to mask: %s")
+	ErrInvalidMaskConfig   = errRun.Code("invalid_mask_config").ErrorPref("invalid masking config file %s: %s")
+	ErrMaskingDisabled     = errRun.Code("masking_disabled").Error("--mask-report and --fail-on-leak cannot be used together with --no-masking")
+	ErrCannotWriteReport   = errRun.Code("cannot_write_mask_report").ErrorPref("cannot write the masking report to %s: %s")
+	ErrSecretsLeaked       = errRun.Code("secrets_leaked").ErrorPref("the output of the command contained %s that had to be masked")
+)
+
+const (
+	// maskReportStderr is the value of --mask-report that prints the report on stderr instead of writing it to a file.
+	maskReportStderr = "stderr"
+	// maskReportFileMode is the mode of a masking report file. The report never contains
+	// the values of secrets, but it does reveal which secrets were leaked.
+	maskReportFileMode = 0600
+	// maskValueLabel is the label in the masking report of the values passed with --mask-value or the masking config file.
+	maskValueLabel = "mask value"
+	// maskRegexLabelPrefix is the prefix of the labels in the masking report of regular expressions.
+	maskRegexLabelPrefix = "regex "
+)
+
+// maskConfig is the format of the masking config file passed with --mask-config.
+type maskConfig struct {
+	// Values are values that are masked in addition to the secrets.
+	Values []string `yaml:"values"`
+	// Regexes are regular expressions of which all matches are masked.
+	Regexes []string `yaml:"regexes"`
+}
+
+// maskingFlags configures how secrets are masked in output. The flags are shared by the run and mask commands.
+type maskingFlags struct {
+	options    masker.Options
+	encodings  string
+	values     []string
+	regexes    []string
+	configFile string
+	report     string
+	failOnLeak bool
+	labels     *secretLabels
+}
+
+// register registers the masking flags on the given clause.
+func (f *maskingFlags) register(clause *cli.CommandClause) {
+	clause.Flag("no-output-buffering", "Disable output buffering. This increases output responsiveness, but decreases the probability that secrets get masked.").BoolVar(&f.options.DisableBuffer)
+	clause.Flag("masking-buffer-period", "The time period for which output is buffered. A higher value increases the probability that secrets get masked but decreases output responsiveness.").Default("50ms").DurationVar(&f.options.BufferDelay)
+	clause.Flag("mask-encodings", "A comma-separated list of encodings in which secrets are masked in addition to their raw form. "+
+		"Supported encodings are "+strings.Replace(defaultMaskEncodings(), ",", ", ", -1)+". Use "+noMaskEncodings+" to only mask the raw secrets.").Default(defaultMaskEncodings()).StringVar(&f.encodings)
+	clause.Flag("mask-value", "A value to mask in addition to the secrets, e.g. a token created at runtime. Can be repeated.").StringsVar(&f.values)
+	clause.Flag("mask-regex", "A regular expression of which all matches are masked, e.g. AKIA[0-9A-Z]{16} for AWS access key IDs. Matches longer than 4096 bytes are not masked. Can be repeated.").StringsVar(&f.regexes)
+	clause.Flag("mask-config", "The path to a YAML file with a list of values and a list of regexes to mask, in addition to the secrets.").ExistingFileVar(&f.configFile)
+	clause.Flag("mask-report", "After all output is written, report how many times each secret was masked on each stream. Secrets are identified by their path, never by their value. "+
+		"Pass a file path to write the report to or "+maskReportStderr+" to print it on stderr.").StringVar(&f.report)
+	clause.Flag("fail-on-leak", "Exit with a non-zero status when any secret was masked in the output. Useful to fail CI builds that print secrets.").BoolVar(&f.failOnLeak)
+}
+
+// configure sets the masker options from the masking flags and the masking config file.
+// It returns the values that should be masked in addition to the secrets.
+func (f *maskingFlags) configure() ([]string, error) {
+	encodings, err := parseMaskEncodings(f.encodings)
+	if err != nil {
+		return nil, err
+	}
+	f.options.Encodings = encodings
+
+	values := f.values
+	regexes := f.regexes
+	if f.configFile != "" {
+		config, err := readMaskConfig(f.configFile)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, config.Values...)
+		regexes = append(regexes, config.Regexes...)
+	}
+
+	f.options.Patterns = make([]*regexp.Regexp, len(regexes))
+	for i, expr := range regexes {
+		f.options.Patterns[i], err = regexp.Compile(expr)
+		if err != nil {
+			return nil, ErrInvalidMaskRegex(expr, err)
+		}
+	}
+	return values, nil
+}
+
+// secretReader returns a secret reader that records the paths of the secrets it reads,
+// so that the secrets can be identified by their path in the masking report.
+func (f *maskingFlags) secretReader(newClient newClientFunc) tpl.SecretReader {
+	return f.secretLabels().reader(newSecretReader(newClient))
+}
+
+// secretLabels returns the paths of the secrets read with secretReader.
+func (f *maskingFlags) secretLabels() *secretLabels {
+	if f.labels == nil {
+		f.labels = &secretLabels{}
+	}
+	return f.labels
+}
+
+// newMasker creates a masker with the configured options that masks the given secrets and values.
+func (f *maskingFlags) newMasker(secrets []string, values []string) *masker.Masker {
+	m := masker.New(nil, &f.options)
+	f.maskSecrets(m, secrets)
+	m.AddLabeledSequences(maskValueLabel, maskSequences(values))
+	return m
+}
+
+// maskSecrets adds the values of the secrets to the masker, labeled with the paths of the secrets.
+func (f *maskingFlags) maskSecrets(m *masker.Masker, secrets []string) {
+	for _, secret := range secrets {
+		if secret != "" {
+			m.AddLabeledSequences(f.secretLabels().label(secret), [][]byte{[]byte(secret)})
+		}
+	}
+}
+
+// reportMasks writes the masking report when requested and returns an error when
+// --fail-on-leak is set and any secret was masked.
+func (f *maskingFlags) reportMasks(report []masker.Mask) error {
+	if f.report != "" {
+		err := f.writeReport(report)
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.failOnLeak && len(report) > 0 {
+		leaked := map[string]bool{}
+		for _, mask := range report {
+			leaked[mask.Label] = true
+		}
+		return ErrSecretsLeaked(pluralize("secret", "secrets", len(leaked)))
+	}
+	return nil
+}
+
+// writeReport writes a table with the number of times every secret was masked on every stream
+// to the file or stderr, as passed with --mask-report.
+func (f *maskingFlags) writeReport(report []masker.Mask) error {
+	patterns := make(map[string]bool, len(f.options.Patterns))
+	for _, pattern := range f.options.Patterns {
+		patterns[pattern.String()] = true
+	}
+
+	buf := &bytes.Buffer{}
+	if len(report) == 0 {
+		fmt.Fprintln(buf, "No secrets were masked.")
+	} else {
+		formatter := newColumnFormatter(buf, 2, []string{"secret", "stream", "count"})
+		for _, mask := range report {
+			label := mask.Label
+			if patterns[label] {
+				label = maskRegexLabelPrefix + label
+			}
+			err := formatter.Write([]string{label, mask.Stream, fmt.Sprint(mask.Count)})
+			if err != nil {
+				return err
+			}
+		}
+		err := formatter.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.report == maskReportStderr {
+		_, err := os.Stderr.Write(buf.Bytes())
+		return err
+	}
+
+	err := ioutil.WriteFile(f.report, buf.Bytes(), maskReportFileMode)
+	if err != nil {
+		return ErrCannotWriteReport(f.report, err)
+	}
+	return nil
+}
+
+// secretLabels records the path of every secret that is read, so that masked secrets
+// are identified by their path in the masking report instead of by their value.
+type secretLabels struct {
+	mutex sync.Mutex
+	// paths maps the values of the secrets to their paths.
+	paths map[string]string
+}
+
+// reader wraps the secret reader to record the paths of the secrets it reads.
+func (l *secretLabels) reader(sr tpl.SecretReader) tpl.SecretReader {
+	return &labelingSecretReader{
+		secretReader: sr,
+		labels:       l,
+	}
+}
+
+// add records the path of the secret with the given value. When multiple secrets
+// have the same value, the path of the first secret is kept.
+func (l *secretLabels) add(path, value string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.paths == nil {
+		l.paths = make(map[string]string)
+	}
+	if _, exists := l.paths[value]; !exists {
+		l.paths[value] = path
+	}
+}
+
+// label returns the path of the secret with the given value.
+func (l *secretLabels) label(value string) string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.paths[value]
+}
+
+// labelingSecretReader is a tpl.SecretReader that records the paths of the secrets it reads in secretLabels.
+// It can be used concurrently.
+type labelingSecretReader struct {
+	secretReader tpl.SecretReader
+	labels       *secretLabels
+}
+
+// ReadSecret reads the secret with the underlying reader and records its path.
+func (r *labelingSecretReader) ReadSecret(path string) (string, error) {
+	secret, err := r.secretReader.ReadSecret(path)
+	if err == nil {
+		r.labels.add(path, secret)
+	}
+	return secret, err
+}
+
+// readMaskConfig reads the masking config file at the given path.
+func readMaskConfig(path string) (*maskConfig, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, ErrCannotReadFile(path, err)
+	}
+
+	config := &maskConfig{}
+	err = yaml.UnmarshalStrict(raw, config)
+	if err != nil {
+		return nil, ErrInvalidMaskConfig(path, err)
+	}
+	return config, nil
+}
+
+// noMaskEncodings is the value of --mask-encodings that disables the masking of encoded secrets.
+const noMaskEncodings = "none"
+
+// defaultMaskEncodings returns the default value of --mask-encodings, which are all supported encodings.
+func defaultMaskEncodings() string {
+	names := make([]string, len(masker.Encodings))
+	for i, encoding := range masker.Encodings {
+		names[i] = string(encoding)
+	}
+	return strings.Join(names, ",")
+}
+
+// parseMaskEncodings parses a comma-separated list of encodings in which secrets should be masked.
+func parseMaskEncodings(value string) ([]masker.Encoding, error) {
+	if value == "" || value == noMaskEncodings {
+		return nil, nil
+	}
+
+	var encodings []masker.Encoding
+	for _, name := range strings.Split(value, ",") {
+		encoding, err := parseMaskEncoding(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		encodings = append(encodings, encoding)
+	}
+	return encodings, nil
+}
+
+func parseMaskEncoding(name string) (masker.Encoding, error) {
+	for _, encoding := range masker.Encodings {
+		if strings.EqualFold(name, string(encoding)) {
+			return encoding, nil
+		}
+	}
+	return "", ErrUnknownMaskEncoding(name, strings.Replace(defaultMaskEncodings(), ",", ", ", -1))
+}
@@ -0,0 +1,258 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// signalBufferSize is the number of received signals that are buffered while they are forwarded to the command.
+	signalBufferSize = 16
+	// restartGracePeriod is the time a command gets to exit after it is sent the terminate signal
+	// for a restart. When it has not exited by then, it is killed.
+	restartGracePeriod = 10 * time.Second
+)
+
+// ExitStatusError is returned by the run command when the command it runs does not exit successfully.
+// It contains the exit status of the command, so that secrethub can exit in the same way.
+type ExitStatusError struct {
+	status int
+	// signal is the signal that terminated the command, or 0 when the command exited by itself.
+	signal syscall.Signal
+}
+
+// newExitStatusError returns the error for a command that exited with the given wait status.
+func newExitStatusError(ws syscall.WaitStatus) *ExitStatusError {
+	if ws.Signaled() {
+		return &ExitStatusError{signal: ws.Signal()}
+	}
+	return &ExitStatusError{status: ws.ExitStatus()}
+}
+
+// Error implements the error interface.
+func (e *ExitStatusError) Error() string {
+	if e.signal != 0 {
+		return fmt.Sprintf("command terminated by signal: %s", e.signal)
+	}
+	return fmt.Sprintf("command exited with status %d", e.status)
+}
+
+// ExitCode returns the code with which secrethub should exit. When the command was terminated
+// by a signal, this is 128 plus the number of the signal, following the convention of shells.
+func (e *ExitStatusError) ExitCode() int {
+	if e.signal != 0 {
+		return 128 + int(e.signal)
+	}
+	return e.status
+}
+
+// Exit terminates secrethub in the same way as the command terminated. When the command was
+// terminated by a signal, secrethub is terminated by the same signal where possible.
+// Otherwise, secrethub exits with the code returned by ExitCode.
+func (e *ExitStatusError) Exit() {
+	if e.signal != 0 {
+		raise(e.signal)
+	}
+	os.Exit(e.ExitCode())
+}
+
+// process is a running command.
+type process interface {
+	Signal(sig os.Signal) error
+	Kill() error
+	// Wait waits for the process to exit. An *ExitStatusError is returned when it does not exit successfully.
+	Wait() error
+}
+
+// execProcess is a process started with os/exec.
+type execProcess struct {
+	cmd *exec.Cmd
+}
+
+// startProcess starts the command and returns the running process.
+func startProcess(cmd *exec.Cmd) (process, error) {
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	return execProcess{cmd: cmd}, nil
+}
+
+// Signal sends the signal to the process.
+func (p execProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+// Kill kills the process.
+func (p execProcess) Kill() error {
+	return p.cmd.Process.Kill()
+}
+
+// Wait waits for the process to exit and converts its exit status to an *ExitStatusError.
+func (p execProcess) Wait() error {
+	err := p.cmd.Wait()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return newExitStatusError(ws)
+		}
+	}
+	return err
+}
+
+// supervisor runs a command and supervises it for its entire lifetime. All relevant signals
+// received by secrethub are forwarded to the command and when the secrets change, the command
+// is either restarted or sent the watch signal.
+type supervisor struct {
+	// start starts the command with the given environment.
+	start   func(env []string) (process, error)
+	signals <-chan os.Signal
+	// handleSignal is called for every signal received. When it returns true, the signal
+	// has been handled and is not forwarded to the command.
+	handleSignal func(sig os.Signal) bool
+	changes      <-chan environmentChange
+	// onChange is called for every change of the secrets, before the command is signaled or restarted.
+	onChange    func(change environmentChange)
+	watchSignal os.Signal
+	// gracePeriod is the time the command gets to exit after it is asked to for a restart, before it is killed.
+	gracePeriod time.Duration
+	stderr      io.Writer
+}
+
+// run starts the command with the given environment and returns when it has exited and does not need to
+// be restarted. An *ExitStatusError is returned when the command does not exit successfully.
+func (s *supervisor) run(env []string) error {
+	err := s.abortOnPendingSignal()
+	if err != nil {
+		return err
+	}
+
+	for {
+		proc, err := s.start(env)
+		if err != nil {
+			return ErrStartFailed(err)
+		}
+
+		exited := make(chan error, 1)
+		go func() {
+			exited <- proc.Wait()
+		}()
+
+		restart := false
+		// kill fires when the command has not exited within the grace period of a restart.
+		var kill <-chan time.Time
+	supervise:
+		for {
+			select {
+			case sig := <-s.signals:
+				if s.handleSignal != nil && s.handleSignal(sig) {
+					continue
+				}
+				if isForwardedSignal(sig) {
+					s.signal(proc, sig)
+				}
+			case change := <-s.changes:
+				if s.onChange != nil {
+					s.onChange(change)
+				}
+				if s.watchSignal != nil {
+					s.signal(proc, s.watchSignal)
+					continue
+				}
+				env = change.env
+				if restart {
+					// The command is already being restarted and is started with the latest environment.
+					continue
+				}
+				fmt.Fprintln(s.stderr, "Secrets have changed, restarting the command.")
+				restart = true
+				kill = s.terminate(proc)
+			case <-kill:
+				fmt.Fprintf(s.stderr, "The command did not exit within %s, killing it.\n", s.gracePeriod)
+				s.kill(proc)
+				kill = nil
+			case err = <-exited:
+				break supervise
+			}
+		}
+
+		// A command that is restarted is expected to exit because it was terminated or killed.
+		if !restart {
+			return err
+		}
+	}
+}
+
+// abortOnPendingSignal discards the signals that were received before the command is started, e.g. while
+// the secrets were fetched. When any of them is a terminating signal, the command is not started and an
+// *ExitStatusError is returned as if the command was terminated by that signal.
+func (s *supervisor) abortOnPendingSignal() error {
+	for {
+		select {
+		case sig := <-s.signals:
+			if isTerminatingSignal(sig) {
+				return &ExitStatusError{signal: sig.(syscall.Signal)}
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// terminate asks the process to exit by sending it the terminate signal and returns a channel that fires
+// when the grace period has passed. When the signal cannot be sent, the process is killed right away.
+func (s *supervisor) terminate(proc process) <-chan time.Time {
+	if terminateSignal == nil {
+		s.kill(proc)
+		return nil
+	}
+
+	err := proc.Signal(terminateSignal)
+	if err != nil {
+		if !isProcessFinishedErr(err) {
+			fmt.Fprintln(s.stderr, ErrSignalFailed(err))
+			s.kill(proc)
+		}
+		return nil
+	}
+	return time.After(s.gracePeriod)
+}
+
+// kill kills the process and reports any errors.
+func (s *supervisor) kill(proc process) {
+	err := proc.Kill()
+	if err != nil && !isProcessFinishedErr(err) {
+		fmt.Fprintln(s.stderr, ErrKillFailed(err))
+	}
+}
+
+// signal sends the given signal to the process and reports any errors.
+func (s *supervisor) signal(proc process, sig os.Signal) {
+	err := proc.Signal(sig)
+	if err != nil && !isProcessFinishedErr(err) {
+		fmt.Fprintln(s.stderr, ErrSignalFailed(err))
+	}
+}
+
+// isProcessFinishedErr returns whether the error is returned because the process has already exited.
+func isProcessFinishedErr(err error) bool {
+	return strings.Contains(err.Error(), "process already finished")
+}
+
+// isTerminatingSignal returns whether the signal asks secrethub to exit.
+func isTerminatingSignal(sig os.Signal) bool {
+	switch sig {
+	case syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM:
+		return true
+	}
+	return false
+}
+
+// isForwardedSignal returns whether a signal received by secrethub should be forwarded to the command.
+func isForwardedSignal(sig os.Signal) bool {
+	return !unforwardedSignals[sig]
+}
@@ -0,0 +1,315 @@
+// +build !windows
+
+package secrethub
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// fakeProcess is a process that exits when it receives a given signal or is killed.
+type fakeProcess struct {
+	signals []os.Signal
+	exitOn  os.Signal
+	exitErr error
+	// signalErr is returned when a signal is sent to the process.
+	signalErr error
+	killed    bool
+	exit      chan error
+}
+
+// newFakeProcess returns a process that exits with exitErr when it receives the exitOn signal.
+// When exitOn is nil, the process exits immediately.
+func newFakeProcess(exitOn os.Signal, exitErr error) *fakeProcess {
+	p := &fakeProcess{
+		exitOn:  exitOn,
+		exitErr: exitErr,
+		exit:    make(chan error, 1),
+	}
+	if exitOn == nil {
+		p.exit <- exitErr
+	}
+	return p
+}
+
+func (p *fakeProcess) Signal(sig os.Signal) error {
+	p.signals = append(p.signals, sig)
+	if p.signalErr != nil {
+		return p.signalErr
+	}
+	if sig == p.exitOn {
+		p.exit <- p.exitErr
+	}
+	return nil
+}
+
+func (p *fakeProcess) Kill() error {
+	p.killed = true
+	select {
+	case p.exit <- &ExitStatusError{signal: syscall.SIGKILL}:
+	default:
+	}
+	return nil
+}
+
+func (p *fakeProcess) Wait() error {
+	return <-p.exit
+}
+
+func TestSupervisor_run(t *testing.T) {
+	testErr := errors.New("test")
+
+	cases := map[string]struct {
+		processes []*fakeProcess
+		startErr  error
+		// pendingSignals are received before the command is started.
+		pendingSignals []os.Signal
+		// signals are received after the command is started.
+		signals         []os.Signal
+		handledSignals  []os.Signal
+		changes         []environmentChange
+		watchSignal     os.Signal
+		gracePeriod     time.Duration
+		expectedEnvs    [][]string
+		expectedSignals [][]os.Signal
+		expectedKills   []bool
+		expectedStderr  string
+		err             error
+	}{
+		"success": {
+			processes:       []*fakeProcess{newFakeProcess(nil, nil)},
+			expectedEnvs:    [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{nil},
+		},
+		"exit status": {
+			processes:       []*fakeProcess{newFakeProcess(nil, &ExitStatusError{status: 3})},
+			expectedEnvs:    [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{nil},
+			err:             &ExitStatusError{status: 3},
+		},
+		"start failed": {
+			startErr: testErr,
+			err:      ErrStartFailed(testErr),
+		},
+		"signals are forwarded": {
+			processes: []*fakeProcess{newFakeProcess(syscall.SIGTERM, &ExitStatusError{signal: syscall.SIGTERM})},
+			signals: []os.Signal{
+				syscall.SIGINT,
+				syscall.SIGCHLD,
+				syscall.SIGUSR1,
+				syscall.SIGURG,
+				syscall.SIGWINCH,
+				syscall.SIGTERM,
+			},
+			expectedEnvs: [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{{
+				syscall.SIGINT,
+				syscall.SIGUSR1,
+				syscall.SIGWINCH,
+				syscall.SIGTERM,
+			}},
+			err: &ExitStatusError{signal: syscall.SIGTERM},
+		},
+		"handled signals are not forwarded": {
+			processes: []*fakeProcess{newFakeProcess(syscall.SIGTERM, nil)},
+			signals: []os.Signal{
+				syscall.SIGWINCH,
+				syscall.SIGINT,
+				syscall.SIGTERM,
+			},
+			handledSignals:  []os.Signal{syscall.SIGWINCH},
+			expectedEnvs:    [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{{syscall.SIGINT, syscall.SIGTERM}},
+		},
+		"terminating signal before start": {
+			pendingSignals: []os.Signal{syscall.SIGWINCH, syscall.SIGINT},
+			err:            &ExitStatusError{signal: syscall.SIGINT},
+		},
+		"other signals before start are discarded": {
+			processes:       []*fakeProcess{newFakeProcess(nil, nil)},
+			pendingSignals:  []os.Signal{syscall.SIGWINCH, syscall.SIGUSR1},
+			expectedEnvs:    [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{nil},
+		},
+		"restart on change": {
+			processes: []*fakeProcess{
+				newFakeProcess(syscall.SIGTERM, &ExitStatusError{signal: syscall.SIGTERM}),
+				newFakeProcess(nil, nil),
+			},
+			changes: []environmentChange{
+				{env: []string{"FOO=baz"}},
+			},
+			expectedEnvs:    [][]string{{"FOO=bar"}, {"FOO=baz"}},
+			expectedSignals: [][]os.Signal{{syscall.SIGTERM}, nil},
+			expectedStderr:  "Secrets have changed, restarting the command.\n",
+		},
+		"kill when the command does not exit on restart": {
+			processes: []*fakeProcess{
+				newFakeProcess(syscall.SIGUSR1, nil),
+				newFakeProcess(nil, nil),
+			},
+			changes: []environmentChange{
+				{env: []string{"FOO=baz"}},
+			},
+			gracePeriod:     10 * time.Millisecond,
+			expectedEnvs:    [][]string{{"FOO=bar"}, {"FOO=baz"}},
+			expectedSignals: [][]os.Signal{{syscall.SIGTERM}, nil},
+			expectedKills:   []bool{true, false},
+			expectedStderr: "Secrets have changed, restarting the command.\n" +
+				"The command did not exit within 10ms, killing it.\n",
+		},
+		"kill when the terminate signal fails on restart": {
+			processes: []*fakeProcess{
+				{signalErr: testErr, exit: make(chan error, 1)},
+				newFakeProcess(nil, nil),
+			},
+			changes: []environmentChange{
+				{env: []string{"FOO=baz"}},
+			},
+			expectedEnvs:    [][]string{{"FOO=bar"}, {"FOO=baz"}},
+			expectedSignals: [][]os.Signal{{syscall.SIGTERM}, nil},
+			expectedKills:   []bool{true, false},
+			expectedStderr: "Secrets have changed, restarting the command.\n" +
+				ErrSignalFailed(testErr).Error() + "\n",
+		},
+		"watch signal on change": {
+			processes: []*fakeProcess{newFakeProcess(syscall.SIGHUP, nil)},
+			changes: []environmentChange{
+				{env: []string{"FOO=baz"}},
+			},
+			watchSignal:     syscall.SIGHUP,
+			expectedEnvs:    [][]string{{"FOO=bar"}},
+			expectedSignals: [][]os.Signal{{syscall.SIGHUP}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			signals := make(chan os.Signal, len(tc.pendingSignals)+len(tc.signals))
+			for _, sig := range tc.pendingSignals {
+				signals <- sig
+			}
+
+			changes := make(chan environmentChange, len(tc.changes))
+			for _, change := range tc.changes {
+				changes <- change
+			}
+
+			gracePeriod := tc.gracePeriod
+			if gracePeriod == 0 {
+				gracePeriod = time.Minute
+			}
+
+			var envs [][]string
+			stderr := &bytes.Buffer{}
+			s := &supervisor{
+				start: func(env []string) (process, error) {
+					if tc.startErr != nil {
+						return nil, tc.startErr
+					}
+					if envs == nil {
+						for _, sig := range tc.signals {
+							signals <- sig
+						}
+					}
+					proc := tc.processes[len(envs)]
+					envs = append(envs, env)
+					return proc, nil
+				},
+				signals: signals,
+				handleSignal: func(sig os.Signal) bool {
+					for _, handled := range tc.handledSignals {
+						if sig == handled {
+							return true
+						}
+					}
+					return false
+				},
+				changes:     changes,
+				watchSignal: tc.watchSignal,
+				gracePeriod: gracePeriod,
+				stderr:      stderr,
+			}
+
+			err := s.run([]string{"FOO=bar"})
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, envs, tc.expectedEnvs)
+			for i, proc := range tc.processes {
+				assert.Equal(t, proc.signals, tc.expectedSignals[i])
+				if tc.expectedKills != nil {
+					assert.Equal(t, proc.killed, tc.expectedKills[i])
+				}
+			}
+			assert.Equal(t, stderr.String(), tc.expectedStderr)
+		})
+	}
+}
+
+func TestExecProcess_Wait(t *testing.T) {
+	cases := map[string]struct {
+		command string
+		err     error
+	}{
+		"success": {
+			command: "exit 0",
+		},
+		"exit status": {
+			command: "exit 4",
+			err:     &ExitStatusError{status: 4},
+		},
+		"terminated by signal": {
+			command: "kill -TERM $$",
+			err:     &ExitStatusError{signal: syscall.SIGTERM},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			proc, err := startProcess(exec.Command("/bin/sh", "-c", tc.command))
+			assert.OK(t, err)
+
+			err = proc.Wait()
+
+			assert.Equal(t, err, tc.err)
+		})
+	}
+}
+
+func TestExitStatusError(t *testing.T) {
+	cases := map[string]struct {
+		err             *ExitStatusError
+		expectedCode    int
+		expectedMessage string
+	}{
+		"exit status": {
+			err:             &ExitStatusError{status: 3},
+			expectedCode:    3,
+			expectedMessage: "command exited with status 3",
+		},
+		"interrupt": {
+			err:             &ExitStatusError{signal: syscall.SIGINT},
+			expectedCode:    130,
+			expectedMessage: "command terminated by signal: interrupt",
+		},
+		"killed": {
+			err:             &ExitStatusError{signal: syscall.SIGKILL},
+			expectedCode:    137,
+			expectedMessage: "command terminated by signal: killed",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.err.ExitCode(), tc.expectedCode)
+			assert.Equal(t, tc.err.Error(), tc.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package secrethub
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Errors
+var (
+	ErrPTYNotSupported = errRun.Code("pty_not_supported").Error("running a command in a pseudo-terminal with --pty is only supported on Linux")
+	ErrCannotOpenPTY   = errRun.Code("cannot_open_pty").ErrorPref("cannot open a pseudo-terminal: %s")
+)
+
+const (
+	// endOfTransmission is the control character that signals the end of the input to a terminal.
+	endOfTransmission = 0x04
+	// ptyDrainTimeout is the maximum time to wait for the output of the pseudo-terminal to be copied after the
+	// command has exited. A background process started by the command can keep the pseudo-terminal open.
+	ptyDrainTimeout = 2 * time.Second
+)
+
+// pseudoTerminal is a pseudo-terminal to which a command is attached, so that the command behaves
+// as if it runs in an interactive terminal, while all of its output still goes through the masker.
+type pseudoTerminal struct {
+	master *os.File
+	slave  *os.File
+	stdin  *os.File
+	// restore restores the state of the terminal of secrethub if it was put in raw mode.
+	restore func() error
+	// copied is closed when all output of the pseudo-terminal has been copied.
+	copied chan struct{}
+	// drainTimeout is the maximum time close waits for the output to be copied.
+	drainTimeout time.Duration
+}
+
+// openPseudoTerminal opens a pseudo-terminal that copies its input from stdin and its output to stdout.
+// When stdin is a terminal, the pseudo-terminal gets the same size and stdin is put in raw mode,
+// so that all input, including control characters such as Ctrl-C, is handled by the pseudo-terminal.
+func openPseudoTerminal(stdin *os.File, stdout io.Writer) (*pseudoTerminal, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &pseudoTerminal{
+		master:       master,
+		slave:        slave,
+		stdin:        stdin,
+		copied:       make(chan struct{}),
+		drainTimeout: ptyDrainTimeout,
+	}
+
+	isTerminal := terminal.IsTerminal(int(stdin.Fd()))
+	if isTerminal {
+		err = t.resize()
+		if err != nil {
+			_ = master.Close()
+			_ = slave.Close()
+			return nil, ErrCannotOpenPTY(err)
+		}
+
+		state, err := terminal.MakeRaw(int(stdin.Fd()))
+		if err != nil {
+			_ = master.Close()
+			_ = slave.Close()
+			return nil, ErrCannotOpenPTY(err)
+		}
+		t.restore = func() error {
+			return terminal.Restore(int(stdin.Fd()), state)
+		}
+	}
+
+	go func() {
+		_, _ = io.Copy(master, stdin)
+		if !isTerminal {
+			// Piped input does not end with Ctrl-D, so the end of the input is signaled to the command explicitly.
+			_, _ = master.Write([]byte{endOfTransmission})
+		}
+	}()
+
+	go func() {
+		// Reading from the pseudo-terminal returns an error once the command has exited
+		// and all its output has been read, so the error is not reported.
+		_, _ = io.Copy(stdout, master)
+		close(t.copied)
+	}()
+
+	return t, nil
+}
+
+// attach makes the pseudo-terminal the controlling terminal of the command and connects it to the stdio of the command.
+func (t *pseudoTerminal) attach(cmd *exec.Cmd) {
+	cmd.Stdin = t.slave
+	cmd.Stdout = t.slave
+	cmd.Stderr = t.slave
+	cmd.SysProcAttr = controllingTerminalAttr()
+}
+
+// resize sets the size of the pseudo-terminal to the size of the terminal of secrethub.
+// The command is sent a SIGWINCH by the kernel when the size changes.
+func (t *pseudoTerminal) resize() error {
+	fd := int(t.stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil
+	}
+
+	width, height, err := terminal.GetSize(fd)
+	if err != nil {
+		return err
+	}
+	return setTerminalSize(t.master, width, height)
+}
+
+// close closes the pseudo-terminal after the command has exited. It waits until all output of
+// the command has been copied, for at most the drain timeout, and restores the terminal of secrethub.
+// The terminal is restored also when closing the pseudo-terminal fails, so that it is never left in raw mode.
+func (t *pseudoTerminal) close() (err error) {
+	if t.restore != nil {
+		defer func() {
+			restoreErr := t.restore()
+			if err == nil {
+				err = restoreErr
+			}
+		}()
+	}
+
+	slaveErr := t.slave.Close()
+
+	select {
+	case <-t.copied:
+	case <-time.After(t.drainTimeout):
+	}
+
+	// Closing the master stops copying the output when the drain timeout has passed. The copying is
+	// waited for again, so that no output is written after the pseudo-terminal is closed.
+	err = t.master.Close()
+	select {
+	case <-t.copied:
+	case <-time.After(t.drainTimeout):
+	}
+
+	if slaveErr != nil {
+		return slaveErr
+	}
+	return err
+}
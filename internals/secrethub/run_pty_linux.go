@@ -0,0 +1,76 @@
+// +build linux
+
+package secrethub
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY opens a new pseudo-terminal and returns its master and slave.
+func openPTY() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, ErrCannotOpenPTY(err)
+	}
+
+	var n uint32
+	err = control(master, func(fd int) error {
+		err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0)
+		if err != nil {
+			return err
+		}
+		n, err = unix.IoctlGetUint32(fd, unix.TIOCGPTN)
+		return err
+	})
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, ErrCannotOpenPTY(err)
+	}
+
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, ErrCannotOpenPTY(err)
+	}
+	return master, slave, nil
+}
+
+// setTerminalSize sets the size of the pseudo-terminal.
+func setTerminalSize(master *os.File, width, height int) error {
+	return control(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{
+			Col: uint16(width),
+			Row: uint16(height),
+		})
+	})
+}
+
+// controllingTerminalAttr returns the attributes that start a command in a new session,
+// with its stdin as the controlling terminal.
+func controllingTerminalAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setsid:  true,
+		Setctty: true,
+	}
+}
+
+// control calls f with the file descriptor of the file, without putting the file in blocking mode like file.Fd() does.
+func control(file *os.File, f func(fd int) error) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fErr error
+	err = conn.Control(func(fd uintptr) {
+		fErr = f(int(fd))
+	})
+	if err != nil {
+		return err
+	}
+	return fErr
+}
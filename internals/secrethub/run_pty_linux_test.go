@@ -0,0 +1,154 @@
+// +build linux
+
+package secrethub
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRunCommand_Run_PTY(t *testing.T) {
+	cases := map[string]struct {
+		command string
+		out     string
+		err     error
+	}{
+		"stdio is a terminal": {
+			command: `test -t 0 && test -t 1 && test -t 2 && echo terminal`,
+			out:     "terminal\r\n",
+		},
+		"output is masked": {
+			command: `cat "$CERT"; echo`,
+			out:     maskString + "\r\n",
+		},
+		"stderr is written to stdout": {
+			command: `echo error >&2`,
+			out:     "error\r\n",
+		},
+		"exit status": {
+			command: `exit 3`,
+			err:     &ExitStatusError{status: 3},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			io := fakeui.NewIO(t)
+			cmd := RunCommand{
+				io:      io,
+				command: []string{"/bin/sh", "-c", tc.command},
+				pty:     true,
+				files: map[string]string{
+					"CERT": "company/app/tls.crt",
+				},
+				environment: &environment{
+					osStat:                       osStatFunc("secrethub.env", os.ErrNotExist),
+					templateVersion:              "auto",
+					dontPromptMissingTemplateVar: true,
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			}
+
+			err := cmd.Run()
+
+			assert.Equal(t, err, tc.err)
+			out, err := io.ReadStdout()
+			assert.OK(t, err)
+			assert.Equal(t, string(out), tc.out)
+		})
+	}
+}
+
+func TestPseudoTerminal_PipedInput(t *testing.T) {
+	stdin, input, err := os.Pipe()
+	assert.OK(t, err)
+	defer stdin.Close()
+
+	stdout := &bytes.Buffer{}
+	pty, err := openPseudoTerminal(stdin, stdout)
+	assert.OK(t, err)
+
+	command := exec.Command("/bin/sh", "-c", `read line && echo "read $line"`)
+	pty.attach(command)
+	proc, err := startProcess(command)
+	assert.OK(t, err)
+
+	_, err = input.Write([]byte("input\n"))
+	assert.OK(t, err)
+	assert.OK(t, input.Close())
+
+	err = proc.Wait()
+	assert.OK(t, err)
+	assert.OK(t, pty.close())
+
+	// The input is echoed by the pseudo-terminal, like it is in an interactive terminal.
+	assert.Equal(t, stdout.String(), "input\r\nread input\r\n")
+}
+
+func TestPseudoTerminal_Close(t *testing.T) {
+	t.Run("background process keeps the pseudo-terminal open", func(t *testing.T) {
+		stdin, input, err := os.Pipe()
+		assert.OK(t, err)
+		defer stdin.Close()
+		defer input.Close()
+
+		pty, err := openPseudoTerminal(stdin, &bytes.Buffer{})
+		assert.OK(t, err)
+		pty.drainTimeout = 100 * time.Millisecond
+
+		// The background process ignores the SIGHUP it is sent when the shell exits, so it keeps running.
+		command := exec.Command("/bin/sh", "-c", `trap "" HUP; sleep 3 &`)
+		pty.attach(command)
+		proc, err := startProcess(command)
+		assert.OK(t, err)
+		assert.OK(t, proc.Wait())
+
+		start := time.Now()
+		assert.OK(t, pty.close())
+		if time.Since(start) > time.Second {
+			t.Errorf("closing the pseudo-terminal took %s", time.Since(start))
+		}
+	})
+
+	t.Run("terminal is restored when closing fails", func(t *testing.T) {
+		stdin, input, err := os.Pipe()
+		assert.OK(t, err)
+		defer stdin.Close()
+		defer input.Close()
+
+		pty, err := openPseudoTerminal(stdin, &bytes.Buffer{})
+		assert.OK(t, err)
+		pty.drainTimeout = 100 * time.Millisecond
+
+		restored := false
+		pty.restore = func() error {
+			restored = true
+			return nil
+		}
+		assert.OK(t, pty.slave.Close())
+
+		err = pty.close()
+		assert.Equal(t, err == nil, false)
+		assert.Equal(t, restored, true)
+	})
+}
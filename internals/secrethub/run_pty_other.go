@@ -0,0 +1,23 @@
+// +build !linux
+
+package secrethub
+
+import (
+	"os"
+	"syscall"
+)
+
+// openPTY returns an error, because pseudo-terminals are only supported on Linux.
+func openPTY() (*os.File, *os.File, error) {
+	return nil, nil, ErrPTYNotSupported
+}
+
+// setTerminalSize returns an error, because pseudo-terminals are only supported on Linux.
+func setTerminalSize(master *os.File, width, height int) error {
+	return ErrPTYNotSupported
+}
+
+// controllingTerminalAttr returns nil, because pseudo-terminals are only supported on Linux.
+func controllingTerminalAttr() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,144 @@
+package secrethub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+	"github.com/secrethub/secrethub-cli/internals/secretspec"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+)
+
+// Errors
+var (
+	ErrCannotCreateSpecDir = errRun.Code("cannot_create_spec_dir").ErrorPref("cannot create a directory for the files of the spec: %s")
+	ErrDuplicateSpecEnvVar = errRun.Code("duplicate_spec_env_var").ErrorPref("environment variable %s is defined more than once in spec %s")
+)
+
+const (
+	// specDirEnvVar is the environment variable that contains the path of the directory
+	// in which the file and inject consumables of the spec are written.
+	specDirEnvVar = "SECRETHUB_SPEC_DIR"
+	specDirPrefix = "secrethub-run-"
+)
+
+// runSpec contains the consumables of a secrets.yml spec that are presented for a single run.
+type runSpec struct {
+	// dir is the private directory in which the files are written.
+	dir string
+	// env maps the names of the environment variables of the env consumables to the paths of their secrets.
+	env map[string]string
+	// files contains the file and inject consumables, which are written to dir.
+	files []secretspec.Consumable
+	// secrets contains the values of the secrets that are written to files, which should be masked.
+	secrets []string
+}
+
+// parseSpec parses the spec file and returns the environment variables of its env consumables.
+// The targets of the file and inject consumables are created in a private temporary directory,
+// which must be removed with cleanup when the command has finished.
+func parseSpec(specFile string) (*runSpec, error) {
+	raw, err := ioutil.ReadFile(specFile)
+	if err != nil {
+		return nil, ErrCannotReadFile(specFile, err)
+	}
+
+	// ioutil.TempDir creates the directory with 0700 permissions, so only the current user can read the files.
+	dir, err := ioutil.TempDir("", specDirPrefix)
+	if err != nil {
+		return nil, ErrCannotCreateSpecDir(err)
+	}
+
+	spec := &runSpec{
+		dir: dir,
+		env: make(map[string]string),
+	}
+	err = spec.parse(specFile, raw)
+	if err != nil {
+		spec.cleanup()
+		return nil, err
+	}
+	return spec, nil
+}
+
+func (s *runSpec) parse(specFile string, raw []byte) error {
+	presenter, err := secretspec.NewPresenter(s.dir, false, secretspec.DefaultParsers...)
+	if err != nil {
+		return err
+	}
+
+	err = presenter.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	for _, consumable := range presenter.Consumables() {
+		envConsumable, ok := consumable.(secretspec.EnvConsumable)
+		if !ok {
+			s.files = append(s.files, consumable)
+			continue
+		}
+
+		for name, path := range envConsumable.EnvVars() {
+			if _, exists := s.env[name]; exists {
+				return ErrDuplicateSpecEnvVar(name, specFile)
+			}
+			s.env[name] = path
+		}
+	}
+	return nil
+}
+
+// write reads the secrets of the file and inject consumables with the secret reader
+// and writes them to the directory of the spec.
+func (s *runSpec) write(sr tpl.SecretReader) error {
+	if len(s.files) == 0 {
+		return nil
+	}
+
+	sources := make(map[string]struct{})
+	for _, file := range s.files {
+		for source := range file.Sources() {
+			sources[source] = struct{}{}
+		}
+	}
+
+	// Read the secrets in a fixed order, so that the same error is reported on every run.
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	secretReader := newConcurrentSecretReader(sr, secretReadWorkers)
+	secretReader.Prefetch(paths)
+
+	secrets := make(map[string]api.SecretVersion, len(paths))
+	for _, path := range paths {
+		data, err := secretReader.ReadSecret(path)
+		if err != nil {
+			return err
+		}
+		secrets[path] = api.SecretVersion{Data: []byte(data)}
+		s.secrets = append(s.secrets, data)
+	}
+
+	for _, file := range s.files {
+		err := file.Set(secrets)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanup removes the directory with the files of the spec.
+func (s *runSpec) cleanup() {
+	err := os.RemoveAll(s.dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not remove the directory %s containing the files of the spec: %s\n", s.dir, err)
+	}
+}
@@ -0,0 +1,142 @@
+package secrethub
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestRunSpec(t *testing.T) {
+	cases := map[string]struct {
+		spec    string
+		env     map[string]string
+		files   map[string]string
+		secrets []string
+		err     func(specFile string) error
+	}{
+		"env and file consumables": {
+			spec: "secrets:\n" +
+				"  - env:\n" +
+				"      vars:\n" +
+				"        DB_USER: company/app/db/user\n" +
+				"  - env:\n" +
+				"      name: other\n" +
+				"      vars:\n" +
+				"        TOKEN: company/app/token\n" +
+				"  - file:\n" +
+				"      source: company/app/tls.crt\n" +
+				"      target: certs/tls.crt\n",
+			env: map[string]string{
+				"DB_USER": "company/app/db/user",
+				"TOKEN":   "company/app/token",
+			},
+			files: map[string]string{
+				"certs/tls.crt": "value of company/app/tls.crt\n",
+			},
+			secrets: []string{"value of company/app/tls.crt"},
+		},
+		"only env consumables": {
+			spec: "secrets:\n" +
+				"  - env:\n" +
+				"      vars:\n" +
+				"        DB_USER: company/app/db/user\n",
+			env: map[string]string{
+				"DB_USER": "company/app/db/user",
+			},
+			files: map[string]string{},
+		},
+		"duplicate variable": {
+			spec: "secrets:\n" +
+				"  - env:\n" +
+				"      vars:\n" +
+				"        DB_USER: company/app/db/user\n" +
+				"  - env:\n" +
+				"      name: other\n" +
+				"      vars:\n" +
+				"        DB_USER: company/app/other/user\n",
+			err: func(specFile string) error {
+				return ErrDuplicateSpecEnvVar("DB_USER", specFile)
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			specFile := filepath.Join(dir, "secrets.yml")
+			writeTestFiles(t, dir, map[string]string{"secrets.yml": tc.spec})
+
+			// Create the spec directory in the test directory, to check that it is removed on failure.
+			tmpDir := os.Getenv("TMPDIR")
+			defer os.Setenv("TMPDIR", tmpDir)
+			err := os.Setenv("TMPDIR", dir)
+			assert.OK(t, err)
+
+			newClient := func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					SecretService: &fakeclient.SecretService{
+						VersionService: &fakeclient.SecretVersionService{
+							GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+								return &api.SecretVersion{Data: []byte("value of " + path)}, nil
+							},
+						},
+					},
+				}, nil
+			}
+
+			spec, err := parseSpec(specFile)
+			if err == nil {
+				err = spec.write(newSecretReader(newClient))
+			}
+			if tc.err != nil {
+				assert.Equal(t, err, tc.err(specFile))
+
+				specDirs, err := filepath.Glob(filepath.Join(dir, specDirPrefix+"*"))
+				assert.OK(t, err)
+				assert.Equal(t, len(specDirs), 0)
+				return
+			}
+			assert.OK(t, err)
+
+			info, err := os.Stat(spec.dir)
+			assert.OK(t, err)
+			assert.Equal(t, info.Mode().Perm(), os.FileMode(0700))
+
+			files := make(map[string]string)
+			err = filepath.Walk(spec.dir, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(spec.dir, path)
+				if err != nil {
+					return err
+				}
+				files[filepath.ToSlash(rel)] = string(content)
+				return nil
+			})
+			assert.OK(t, err)
+
+			sort.Strings(spec.secrets)
+			assert.Equal(t, spec.env, tc.env)
+			assert.Equal(t, files, tc.files)
+			assert.Equal(t, spec.secrets, tc.secrets)
+
+			spec.cleanup()
+			_, err = os.Stat(spec.dir)
+			assert.Equal(t, os.IsNotExist(err), true)
+		})
+	}
+}
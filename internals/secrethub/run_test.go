@@ -508,7 +508,7 @@ func TestRunCommand_Run(t *testing.T) {
 				},
 				ignoreMissingSecrets: false,
 			},
-			err: api.ErrSecretNotFound,
+			err: ErrResolveEnvVar("missing", provenance{source: envSourceEnvar}, api.ErrSecretNotFound),
 		},
 		"missing secret ignored": {
 			command: RunCommand{
@@ -563,8 +563,8 @@ func TestRunCommand_Run(t *testing.T) {
 		"invalid template var: start with a number": {
 			command: RunCommand{
 				environment: &environment{
-					osStat:  osStatNotExist,
-					envFile: "secrethub.env",
+					osStat:   osStatNotExist,
+					envFiles: []string{"secrethub.env"},
 					templateVars: map[string]string{
 						"0foo": "value",
 					},
@@ -576,8 +576,8 @@ func TestRunCommand_Run(t *testing.T) {
 		"invalid template var: illegal character": {
 			command: RunCommand{
 				environment: &environment{
-					osStat:  osStatNotExist,
-					envFile: "secrethub.env",
+					osStat:   osStatNotExist,
+					envFiles: []string{"secrethub.env"},
 					templateVars: map[string]string{
 						"foo@bar": "value",
 					},
@@ -606,7 +606,7 @@ func TestRunCommand_Run(t *testing.T) {
 					}, nil
 				},
 			},
-			err: api.ErrSecretNotFound,
+			err: ErrResolveEnvVar("TEST", provenance{source: envSourceReference}, api.ErrSecretNotFound),
 		},
 		"os env secret not found ignored": {
 			command: RunCommand{
@@ -676,7 +676,7 @@ func TestRunCommand_environment(t *testing.T) {
 				environment: &environment{
 					osStat:          osStatFunc("secrethub.env", nil),
 					readFile:        readFileFunc("secrethub.env", "TEST={{path/to/secret}"),
-					envFile:         "secrethub.env",
+					envFiles:        []string{"secrethub.env"},
 					templateVersion: "2",
 				},
 			},
@@ -700,7 +700,7 @@ func TestRunCommand_environment(t *testing.T) {
 		"custom env file does not exist": {
 			command: RunCommand{
 				environment: &environment{
-					envFile: "foo.env",
+					envFiles: []string{"foo.env"},
 					readFile: func(filename string) ([]byte, error) {
 						if filename == "foo.env" {
 							return nil, &os.PathError{Op: "open", Path: "foo.env", Err: os.ErrNotExist}
@@ -715,7 +715,7 @@ func TestRunCommand_environment(t *testing.T) {
 			command: RunCommand{
 				environment: &environment{
 					osStat:          osStatFunc("foo.env", nil),
-					envFile:         "foo.env",
+					envFiles:        []string{"foo.env"},
 					templateVersion: "2",
 					readFile:        readFileFunc("foo.env", "TEST=test"),
 				},
@@ -728,7 +728,7 @@ func TestRunCommand_environment(t *testing.T) {
 				environment: &environment{
 					osStat:          osStatFunc("secrethub.env", nil),
 					readFile:        readFileFunc("secrethub.env", "TEST= {{ unexistent/secret/path }}"),
-					envFile:         "secrethub.env",
+					envFiles:        []string{"secrethub.env"},
 					templateVersion: "2",
 				},
 				newClient: func() (secrethub.ClientInterface, error) {
@@ -743,14 +743,14 @@ func TestRunCommand_environment(t *testing.T) {
 					}, nil
 				},
 			},
-			err: ErrParsingTemplate("secrethub.env", api.ErrSecretNotFound),
+			err: ErrResolveEnvVar("TEST", provenance{source: "env file secrethub.env", file: "secrethub.env", line: 1}, api.ErrSecretNotFound),
 		},
 		"envar flag has precedence over env file": {
 			command: RunCommand{
 				environment: &environment{
 					osStat:   osStatFunc("secrethub.env", nil),
 					readFile: readFileFunc("secrethub.env", "TEST=aaa"),
-					envFile:  "secrethub.env",
+					envFiles: []string{"secrethub.env"},
 					envar: map[string]string{
 						"TEST": "test/test/test",
 					},
@@ -990,7 +990,7 @@ func TestRunCommand_environment(t *testing.T) {
 				ignoreMissingSecrets: true,
 				environment: &environment{
 					osStat:   osStatFunc("secrethub.env", nil),
-					envFile:  "secrethub.env",
+					envFiles: []string{"secrethub.env"},
 					readFile: readFileFunc("secrethub.env", ""),
 					envar: map[string]string{
 						"TEST": "test/test/test",
@@ -1019,7 +1019,7 @@ func TestRunCommand_environment(t *testing.T) {
 					osStat:                       osStatFunc("secrethub.env", nil),
 					readFile:                     readFileFunc("secrethub.env", "TEST = {{ test/$variable/test }}"),
 					dontPromptMissingTemplateVar: true,
-					envFile:                      "secrethub.env",
+					envFiles:                     []string{"secrethub.env"},
 					templateVersion:              "2",
 				},
 				newClient: func() (secrethub.ClientInterface, error) {
@@ -1034,7 +1034,7 @@ func TestRunCommand_environment(t *testing.T) {
 					}, nil
 				},
 			},
-			err: ErrParsingTemplate("secrethub.env", tpl.ErrTemplateVarNotFound("variable")),
+			err: ErrResolveEnvVar("TEST", provenance{source: "env file secrethub.env", file: "secrethub.env", line: 1}, tpl.ErrTemplateVarNotFound("variable")),
 		},
 		"template var set in os environment": {
 			command: RunCommand{
@@ -1058,7 +1058,7 @@ func TestRunCommand_environment(t *testing.T) {
 					}, nil
 				},
 			},
-			err: ErrParsingTemplate("secrethub.env", api.ErrSecretNotFound),
+			err: ErrResolveEnvVar("TEST", provenance{source: "env file secrethub.env", file: "secrethub.env", line: 1}, api.ErrSecretNotFound),
 		},
 		"template var set by flag": {
 			command: RunCommand{
@@ -1082,7 +1082,7 @@ func TestRunCommand_environment(t *testing.T) {
 					}, nil
 				},
 			},
-			err: ErrParsingTemplate("secrethub.env", api.ErrSecretNotFound),
+			err: ErrResolveEnvVar("TEST", provenance{source: "env file secrethub.env", file: "secrethub.env", line: 1}, api.ErrSecretNotFound),
 		},
 		"template var set by flag has precedence over var set by environment": {
 			command: RunCommand{
@@ -1110,6 +1110,31 @@ func TestRunCommand_environment(t *testing.T) {
 			},
 			expectedEnv: []string{"TEST=foo", "SECRETHUB_VAR_VARIABLE=bar"},
 		},
+		"spec env consumables": {
+			command: RunCommand{
+				specDir: "/tmp/secrethub-run-123",
+				environment: &environment{
+					osStat:   osStatFunc("secrethub.env", os.ErrNotExist),
+					specFile: "secrets.yml",
+					specEnv: map[string]string{
+						"TEST": "test/test/test",
+					},
+				},
+				newClient: func() (secrethub.ClientInterface, error) {
+					return fakeclient.Client{
+						SecretService: &fakeclient.SecretService{
+							VersionService: &fakeclient.SecretVersionService{
+								GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+									return &api.SecretVersion{Data: []byte("bbb")}, nil
+								},
+							},
+						},
+					}, nil
+				},
+			},
+			expectedSecrets: []string{"bbb"},
+			expectedEnv:     []string{"TEST=bbb", "SECRETHUB_SPEC_DIR=/tmp/secrethub-run-123"},
+		},
 		"v1 template syntax success": {
 			command: RunCommand{
 				command: []string{"/bin/sh", "./test.sh"},
@@ -1179,7 +1204,7 @@ func TestRunCommand_RunWithFile(t *testing.T) {
 				environment: &environment{
 					osStat:   osStatOnlySecretHubEnv,
 					readFile: readFileWithContent(""),
-					envFile:  "secrethub.env",
+					envFiles: []string{"secrethub.env"},
 					envar: map[string]string{
 						"TEST": "test/test/test",
 					},
@@ -1205,7 +1230,7 @@ func TestRunCommand_RunWithFile(t *testing.T) {
 				command: []string{"/bin/sh", "./test.sh"},
 				environment: &environment{
 					osStat:   osStatOnlySecretHubEnv,
-					envFile:  "secrethub.env",
+					envFiles: []string{"secrethub.env"},
 					readFile: readFileWithContent(""),
 					envar: map[string]string{
 						"TEST": "test/test/test",
@@ -0,0 +1,111 @@
+package secrethub
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors
+var (
+	ErrUnknownSignal = errRun.Code("unknown_signal").ErrorPref("unknown signal: %s")
+)
+
+// environmentWatcher periodically sources the environment of a run command
+// and reports when any of the resolved values has changed.
+type environmentWatcher struct {
+	interval time.Duration
+	source   func() ([]string, []string, error)
+	current  []string
+	errOut   io.Writer
+}
+
+// environmentChange is a newly sourced environment and the secret values it contains.
+type environmentChange struct {
+	env     []string
+	secrets []string
+}
+
+// newEnvironmentWatcher creates a watcher that sources the environment every interval
+// and compares it with the given initial environment.
+func newEnvironmentWatcher(interval time.Duration, initial []string, source func() ([]string, []string, error), errOut io.Writer) *environmentWatcher {
+	return &environmentWatcher{
+		interval: interval,
+		source:   source,
+		current:  sortedCopy(initial),
+		errOut:   errOut,
+	}
+}
+
+// watch starts sourcing the environment in the background and sends every changed
+// environment on the returned channel. It stops when the done channel is closed.
+// Errors while sourcing the environment are reported and the current environment is kept.
+func (w *environmentWatcher) watch(done <-chan struct{}) <-chan environmentChange {
+	changes := make(chan environmentChange)
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				change, changed, err := w.check()
+				if err != nil {
+					fmt.Fprintf(w.errOut, "Could not refresh secrets, keeping the current values: %s\n", err)
+					continue
+				}
+				if !changed {
+					continue
+				}
+
+				select {
+				case changes <- change:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return changes
+}
+
+// check sources the environment once and returns whether it differs from the previous one.
+func (w *environmentWatcher) check() (environmentChange, bool, error) {
+	env, secrets, err := w.source()
+	if err != nil {
+		return environmentChange{}, false, err
+	}
+
+	sorted := sortedCopy(env)
+	if reflect.DeepEqual(sorted, w.current) {
+		return environmentChange{}, false, nil
+	}
+	w.current = sorted
+
+	return environmentChange{
+		env:     env,
+		secrets: secrets,
+	}, true, nil
+}
+
+func sortedCopy(values []string) []string {
+	res := make([]string, len(values))
+	copy(res, values)
+	sort.Strings(res)
+	return res
+}
+
+// parseSignal returns the signal with the given name, e.g. HUP or SIGHUP.
+func parseSignal(name string) (os.Signal, error) {
+	sig, ok := signalsByName[strings.TrimPrefix(strings.ToUpper(name), "SIG")]
+	if !ok {
+		return nil, ErrUnknownSignal(name)
+	}
+	return sig, nil
+}
@@ -0,0 +1,115 @@
+package secrethub
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+func TestEnvironmentWatcher_check(t *testing.T) {
+	testErr := errors.New("test error")
+
+	cases := map[string]struct {
+		initial  []string
+		env      []string
+		secrets  []string
+		err      error
+		expected environmentChange
+		changed  bool
+	}{
+		"unchanged": {
+			initial: []string{"A=1", "B=2"},
+			env:     []string{"B=2", "A=1"},
+			secrets: []string{"2"},
+			changed: false,
+		},
+		"changed": {
+			initial: []string{"A=1", "B=2"},
+			env:     []string{"A=1", "B=3"},
+			secrets: []string{"3"},
+			expected: environmentChange{
+				env:     []string{"A=1", "B=3"},
+				secrets: []string{"3"},
+			},
+			changed: true,
+		},
+		"variable added": {
+			initial: []string{"A=1"},
+			env:     []string{"A=1", "B=2"},
+			secrets: []string{"2"},
+			expected: environmentChange{
+				env:     []string{"A=1", "B=2"},
+				secrets: []string{"2"},
+			},
+			changed: true,
+		},
+		"source error": {
+			initial: []string{"A=1"},
+			err:     testErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			source := func() ([]string, []string, error) {
+				return tc.env, tc.secrets, tc.err
+			}
+			watcher := newEnvironmentWatcher(0, tc.initial, source, nil)
+
+			actual, changed, err := watcher.check()
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, changed, tc.changed)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
+
+func TestEnvironmentWatcher_check_UpdatesCurrent(t *testing.T) {
+	source := func() ([]string, []string, error) {
+		return []string{"A=2"}, []string{"2"}, nil
+	}
+	watcher := newEnvironmentWatcher(0, []string{"A=1"}, source, nil)
+
+	_, changed, err := watcher.check()
+	assert.OK(t, err)
+	assert.Equal(t, changed, true)
+
+	_, changed, err = watcher.check()
+	assert.OK(t, err)
+	assert.Equal(t, changed, false)
+}
+
+func TestParseSignal(t *testing.T) {
+	cases := map[string]struct {
+		name string
+		err  error
+	}{
+		"short name": {
+			name: "HUP",
+		},
+		"full name": {
+			name: "SIGHUP",
+		},
+		"lowercase": {
+			name: "hup",
+		},
+		"unknown": {
+			name: "FOO",
+			err:  ErrUnknownSignal("FOO"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sig, err := parseSignal(tc.name)
+
+			assert.Equal(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, sig, syscall.SIGHUP)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package secrethub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/crypto"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+)
+
+// Errors
+var (
+	ErrInvalidCacheMode   = errMain.Code("invalid_cache_mode").ErrorPref("invalid cache mode: %s. Options are off, prefer and fallback")
+	ErrCacheNotSupported  = errMain.Code("cache_not_supported").ErrorPref("the secret cache cannot be used with the %s identity provider, only with the key identity provider")
+	errSecretCacheUnknown = errors.New("the secret is not in the cache")
+)
+
+const (
+	cacheModeOff      = "off"
+	cacheModePrefer   = "prefer"
+	cacheModeFallback = "fallback"
+
+	defaultCacheTTL = 24 * time.Hour
+
+	// cacheFileMode is the file mode of the files in the cache directory.
+	cacheFileMode = 0600
+	// cacheDirFileMode is the file mode of the cache directory.
+	cacheDirFileMode = 0700
+)
+
+// secretCacheDir returns the directory in which the secret cache is stored.
+func secretCacheDir(configDir string) string {
+	return filepath.Join(configDir, "cache")
+}
+
+// secretCache stores secret versions in the configuration directory.
+//
+// The secret versions are encrypted with a randomly generated cache key. The cache key
+// itself is wrapped with the credential that is used to decrypt the account key, so that
+// the cache can be read without reaching the API, but only with the same credential.
+type secretCache struct {
+	dir       string
+	ttl       time.Duration
+	encrypter credentials.Encrypter
+	decrypter credentials.Decrypter
+	now       func() time.Time
+
+	lock sync.Mutex
+	key  *crypto.SymmetricKey
+}
+
+// newSecretCache creates a secret cache in the given directory. Cached secret versions
+// expire after the given TTL.
+func newSecretCache(dir string, ttl time.Duration, encrypter credentials.Encrypter, decrypter credentials.Decrypter) *secretCache {
+	return &secretCache{
+		dir:       dir,
+		ttl:       ttl,
+		encrypter: encrypter,
+		decrypter: decrypter,
+		now:       time.Now,
+	}
+}
+
+// secretCacheEntry is the format in which a secret version is stored in the cache.
+type secretCacheEntry struct {
+	CachedAt time.Time            `json:"cached_at"`
+	Version  crypto.CiphertextAES `json:"version"`
+}
+
+// get returns the cached secret version for the given path. It returns
+// errSecretCacheUnknown when the path is not cached or when the cached version has expired.
+func (c *secretCache) get(path string) (*api.SecretVersion, error) {
+	raw, err := ioutil.ReadFile(c.entryPath(path))
+	if os.IsNotExist(err) {
+		return nil, errSecretCacheUnknown
+	} else if err != nil {
+		return nil, err
+	}
+
+	entry := secretCacheEntry{}
+	err = json.Unmarshal(raw, &entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.now().After(entry.CachedAt.Add(c.ttl)) {
+		_ = os.Remove(c.entryPath(path))
+		return nil, errSecretCacheUnknown
+	}
+
+	key, err := c.cacheKey(false)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := key.Decrypt(entry.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	version := &api.SecretVersion{}
+	err = json.Unmarshal(plaintext, version)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// set stores the secret version for the given path in the cache.
+func (c *secretCache) set(path string, version *api.SecretVersion) error {
+	key, err := c.cacheKey(true)
+	if err != nil {
+		return err
+	}
+
+	cached := *version
+	cached.SecretKey = nil
+	plaintext, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := key.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(secretCacheEntry{
+		CachedAt: c.now(),
+		Version:  ciphertext,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.entryPath(path)), cacheDirFileMode)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.entryPath(path), raw, cacheFileMode)
+}
+
+// remove removes the given path from the cache.
+func (c *secretCache) remove(path string) error {
+	err := os.Remove(c.entryPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// entryPath returns the path of the file in which the secret version of the
+// given path is cached. The secret path is hashed to not expose it on disk.
+func (c *secretCache) entryPath(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return filepath.Join(c.dir, "secrets", hex.EncodeToString(hash[:]))
+}
+
+// cacheKey returns the key the cached secret versions are encrypted with. When the
+// key does not exist or cannot be decrypted with the credential, a new key is created
+// if create is true. Creating a key removes all secret versions cached with the old key.
+func (c *secretCache) cacheKey(create bool) (*crypto.SymmetricKey, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.key != nil {
+		return c.key, nil
+	}
+
+	keyPath := filepath.Join(c.dir, "key")
+	raw, err := ioutil.ReadFile(keyPath)
+	if err == nil {
+		wrapped := api.EncryptedData{}
+		err = json.Unmarshal(raw, &wrapped)
+		if err == nil {
+			var key []byte
+			key, err = c.decrypter.Unwrap(&wrapped)
+			if err == nil {
+				c.key = crypto.NewSymmetricKey(key)
+				return c.key, nil
+			}
+		}
+	}
+	if !create {
+		return nil, errSecretCacheUnknown
+	}
+
+	// The cache was written with another credential or not written at all.
+	err = os.RemoveAll(c.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.GenerateSymmetricKey()
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := c.encrypter.Wrap(key.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err = json.Marshal(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(c.dir, cacheDirFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ioutil.WriteFile(keyPath, raw, cacheFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	c.key = key
+	return c.key, nil
+}
+
+// cachedClient wraps a client to read secret versions from the secret cache.
+// All other calls are passed to the wrapped client.
+type cachedClient struct {
+	secrethub.ClientInterface
+	cache *secretCache
+	mode  string
+}
+
+// newCachedClient wraps the client to use the cache with the given cache mode.
+func newCachedClient(client secrethub.ClientInterface, cache *secretCache, mode string) secrethub.ClientInterface {
+	return cachedClient{
+		ClientInterface: client,
+		cache:           cache,
+		mode:            mode,
+	}
+}
+
+// Secrets returns a SecretService that reads secret versions from the cache.
+func (c cachedClient) Secrets() secrethub.SecretService {
+	return cachedSecretService{
+		SecretService: c.ClientInterface.Secrets(),
+		cache:         c.cache,
+		mode:          c.mode,
+	}
+}
+
+type cachedSecretService struct {
+	secrethub.SecretService
+	cache *secretCache
+	mode  string
+}
+
+// Read reads the secret version at the given path using the cache.
+func (s cachedSecretService) Read(path string) (*api.SecretVersion, error) {
+	return s.Versions().GetWithData(path)
+}
+
+// ReadString reads the secret version at the given path using the cache and returns its data as a string.
+func (s cachedSecretService) ReadString(path string) (string, error) {
+	version, err := s.Read(path)
+	if err != nil {
+		return "", err
+	}
+	return string(version.Data), nil
+}
+
+// Versions returns a SecretVersionService that reads secret versions from the cache.
+func (s cachedSecretService) Versions() secrethub.SecretVersionService {
+	return cachedSecretVersionService{
+		SecretVersionService: s.SecretService.Versions(),
+		cache:                s.cache,
+		mode:                 s.mode,
+	}
+}
+
+type cachedSecretVersionService struct {
+	secrethub.SecretVersionService
+	cache *secretCache
+	mode  string
+}
+
+// GetWithData gets the secret version at the given path with its data.
+//
+// In prefer mode, a cached version is returned when it has not expired and the API is only
+// used when it is not cached. In fallback mode, the API is always used and the cached version
+// is only returned when the API cannot be reached. Versions read from the API are cached and
+// secrets that no longer exist are removed from the cache.
+func (s cachedSecretVersionService) GetWithData(path string) (*api.SecretVersion, error) {
+	if s.mode == cacheModePrefer {
+		version, err := s.cache.get(path)
+		if err == nil {
+			return version, nil
+		}
+	}
+
+	version, err := s.SecretVersionService.GetWithData(path)
+	if err == nil {
+		// Failing to cache a secret should not prevent it from being used.
+		_ = s.cache.set(path, version)
+		return version, nil
+	}
+
+	if api.IsErrNotFound(err) {
+		_ = s.cache.remove(path)
+		return nil, err
+	}
+
+	if s.mode == cacheModeFallback && isAPIUnavailable(err) {
+		version, cacheErr := s.cache.get(path)
+		if cacheErr == nil {
+			return version, nil
+		}
+	}
+
+	return nil, err
+}
+
+// isAPIUnavailable returns whether the error indicates that the API could not be
+// reached or failed to handle the request, as opposed to refusing the request.
+func isAPIUnavailable(err error) bool {
+	var statusErr errio.PublicStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	return true
+}
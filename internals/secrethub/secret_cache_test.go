@@ -0,0 +1,215 @@
+package secrethub
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/internals/errio"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/credentials"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func newTestSecretCache(t *testing.T, dir string) *secretCache {
+	credential, err := credentials.GenerateRSACredential(1024)
+	assert.OK(t, err)
+
+	return newSecretCache(dir, time.Hour, credential, credential)
+}
+
+func TestSecretCache(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	cache := newTestSecretCache(t, dir)
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	_, err := cache.get("namespace/repo/secret")
+	assert.Equal(t, err, errSecretCacheUnknown)
+
+	err = cache.set("namespace/repo/secret", &api.SecretVersion{Version: 2, Data: []byte("foo")})
+	assert.OK(t, err)
+
+	actual, err := cache.get("namespace/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, actual.Version, 2)
+	assert.Equal(t, actual.Data, []byte("foo"))
+
+	// A new cache with the same credential can read the cached secrets.
+	reopened := newSecretCache(dir, time.Hour, cache.encrypter, cache.decrypter)
+	reopened.now = cache.now
+	actual, err = reopened.get("namespace/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, actual.Data, []byte("foo"))
+
+	// A cache with another credential cannot.
+	other := newTestSecretCache(t, dir)
+	other.now = cache.now
+	_, err = other.get("namespace/repo/secret")
+	assert.Equal(t, err, errSecretCacheUnknown)
+
+	// Expired secrets are not returned.
+	now = now.Add(2 * time.Hour)
+	_, err = cache.get("namespace/repo/secret")
+	assert.Equal(t, err, errSecretCacheUnknown)
+}
+
+func TestCachedSecretVersionService_GetWithData(t *testing.T) {
+	errUnavailable := errio.Namespace("test").Code("unavailable").StatusError("service unavailable", http.StatusServiceUnavailable)
+	errForbidden := errio.Namespace("test").Code("forbidden").StatusError("forbidden", http.StatusForbidden)
+
+	cases := map[string]struct {
+		mode     string
+		cached   bool
+		apiErr   error
+		apiCalls int
+		expected string
+		err      error
+	}{
+		"prefer cached": {
+			mode:     cacheModePrefer,
+			cached:   true,
+			apiCalls: 0,
+			expected: "cached",
+		},
+		"prefer not cached": {
+			mode:     cacheModePrefer,
+			apiCalls: 1,
+			expected: "api",
+		},
+		"fallback api available": {
+			mode:     cacheModeFallback,
+			cached:   true,
+			apiCalls: 1,
+			expected: "api",
+		},
+		"fallback api unavailable": {
+			mode:     cacheModeFallback,
+			cached:   true,
+			apiErr:   errUnavailable,
+			apiCalls: 1,
+			expected: "cached",
+		},
+		"fallback api unreachable": {
+			mode:     cacheModeFallback,
+			cached:   true,
+			apiErr:   errors.New("dial tcp: connection refused"),
+			apiCalls: 1,
+			expected: "cached",
+		},
+		"fallback api unavailable not cached": {
+			mode:     cacheModeFallback,
+			apiErr:   errUnavailable,
+			apiCalls: 1,
+			err:      errUnavailable,
+		},
+		"fallback access denied": {
+			mode:     cacheModeFallback,
+			cached:   true,
+			apiErr:   errForbidden,
+			apiCalls: 1,
+			err:      errForbidden,
+		},
+		"fallback not found": {
+			mode:     cacheModeFallback,
+			cached:   true,
+			apiErr:   api.ErrSecretNotFound,
+			apiCalls: 1,
+			err:      api.ErrSecretNotFound,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			cache := newTestSecretCache(t, dir)
+			if tc.cached {
+				err := cache.set("namespace/repo/secret", &api.SecretVersion{Data: []byte("cached")})
+				assert.OK(t, err)
+			}
+
+			apiCalls := 0
+			client := newCachedClient(fakeclient.Client{
+				SecretService: &fakeclient.SecretService{
+					VersionService: &fakeclient.SecretVersionService{
+						GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+							apiCalls++
+							if tc.apiErr != nil {
+								return nil, tc.apiErr
+							}
+							return &api.SecretVersion{Data: []byte("api")}, nil
+						},
+					},
+				},
+			}, cache, tc.mode)
+
+			actual, err := client.Secrets().Versions().GetWithData("namespace/repo/secret")
+
+			assert.Equal(t, err, tc.err)
+			assert.Equal(t, apiCalls, tc.apiCalls)
+			if tc.err == nil {
+				assert.Equal(t, string(actual.Data), tc.expected)
+			}
+		})
+	}
+}
+
+func TestCachedClient_NotFoundRemovesCachedSecret(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	cache := newTestSecretCache(t, dir)
+	err := cache.set("namespace/repo/secret", &api.SecretVersion{Data: []byte("cached")})
+	assert.OK(t, err)
+
+	client := newCachedClient(fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					return nil, api.ErrSecretNotFound
+				},
+			},
+		},
+	}, cache, cacheModeFallback)
+
+	_, err = client.Secrets().Versions().GetWithData("namespace/repo/secret")
+	assert.Equal(t, err, api.ErrSecretNotFound)
+
+	_, err = cache.get("namespace/repo/secret")
+	assert.Equal(t, err, errSecretCacheUnknown)
+}
+
+func TestCachedClient_CachedSecretsAreMasked(t *testing.T) {
+	dir, cleanup := testdata.tempDir(t)
+	defer cleanup()
+
+	cache := newTestSecretCache(t, dir)
+	err := cache.set("namespace/repo/secret", &api.SecretVersion{Data: []byte("cached")})
+	assert.OK(t, err)
+
+	client := newCachedClient(fakeclient.Client{
+		SecretService: &fakeclient.SecretService{
+			VersionService: &fakeclient.SecretVersionService{
+				GetWithDataFunc: func(path string) (*api.SecretVersion, error) {
+					t.Fatal("the secret should be read from the cache")
+					return nil, nil
+				},
+			},
+		},
+	}, cache, cacheModePrefer)
+	sr := newBufferedSecretReader(newSecretReader(func() (secrethub.ClientInterface, error) {
+		return client, nil
+	}))
+
+	value, err := sr.ReadSecret("namespace/repo/secret")
+	assert.OK(t, err)
+	assert.Equal(t, value, "cached")
+	assert.Equal(t, sr.Values(), []string{"cached"})
+}
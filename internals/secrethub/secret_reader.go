@@ -1,6 +1,9 @@
 package secrethub
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
 	"github.com/secrethub/secrethub-go/internals/api"
 )
@@ -57,6 +60,151 @@ func (sr *bufferedSecretReader) ReadSecret(path string) (string, error) {
 	return secret, err
 }
 
+// secretReadWorkers is the maximum number of secrets that are read concurrently.
+const secretReadWorkers = 8
+
+type concurrentSecretReader struct {
+	secretReader tpl.SecretReader
+	workers      int
+
+	lock  sync.Mutex
+	reads map[string]*secretRead
+}
+
+// secretRead is the result of reading a single secret path.
+type secretRead struct {
+	done  chan struct{}
+	value string
+	err   error
+}
+
+// newConcurrentSecretReader wraps a secret reader so that secrets can be prefetched
+// concurrently with at most the given number of workers. Every path is only read once:
+// subsequent reads of the same path return the cached value or error.
+func newConcurrentSecretReader(sr tpl.SecretReader, workers int) *concurrentSecretReader {
+	return &concurrentSecretReader{
+		secretReader: sr,
+		workers:      workers,
+		reads:        map[string]*secretRead{},
+	}
+}
+
+// Prefetch reads the secrets at the given paths concurrently and blocks until all
+// of them are read. Errors are not returned, but cached so that they are returned by
+// ReadSecret. This way, callers can report the error of the first secret in their own
+// order instead of the error that happened to arrive first.
+//
+// The client lazily caches the keys of the account and of every repository and cannot
+// do so concurrently. Therefore, the first secret of every repository is read before
+// the other secrets are read concurrently.
+func (sr *concurrentSecretReader) Prefetch(paths []string) {
+	var remaining []string
+	warmedUp := map[string]bool{}
+	for _, path := range paths {
+		repo := secretRepoPath(path)
+		if _, ok := warmedUp[repo]; ok {
+			if warmedUp[repo] {
+				remaining = append(remaining, path)
+			} else {
+				_, _ = sr.ReadSecret(path)
+			}
+			continue
+		}
+
+		_, err := sr.ReadSecret(path)
+		// When the secret does not exist, the keys have been cached before the secret was requested.
+		warmedUp[repo] = err == nil || api.IsErrNotFound(err)
+	}
+
+	workers := make(chan struct{}, sr.workers)
+	var wg sync.WaitGroup
+	for _, path := range remaining {
+		read, first := sr.getRead(path)
+		if !first {
+			continue
+		}
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(path string) {
+			defer wg.Done()
+			sr.read(path, read)
+			<-workers
+		}(path)
+	}
+	wg.Wait()
+}
+
+// ReadSecret returns the result of a previous read of the secret or uses
+// the underlying secret reader to read it when it has not been read before.
+// It is safe to call ReadSecret from multiple goroutines.
+func (sr *concurrentSecretReader) ReadSecret(path string) (string, error) {
+	read, first := sr.getRead(path)
+	if first {
+		sr.read(path, read)
+	}
+	<-read.done
+	return read.value, read.err
+}
+
+// getRead returns the read of the given path and whether
+// the caller is the first to request it and should perform it.
+func (sr *concurrentSecretReader) getRead(path string) (*secretRead, bool) {
+	sr.lock.Lock()
+	defer sr.lock.Unlock()
+
+	read, ok := sr.reads[path]
+	if ok {
+		return read, false
+	}
+
+	read = &secretRead{
+		done: make(chan struct{}),
+	}
+	sr.reads[path] = read
+	return read, true
+}
+
+// read reads the secret with the underlying secret reader and stores the result.
+func (sr *concurrentSecretReader) read(path string, read *secretRead) {
+	read.value, read.err = sr.secretReader.ReadSecret(path)
+	close(read.done)
+}
+
+// secretRepoPath returns the namespace and repository of a secret path.
+func secretRepoPath(path string) string {
+	elements := strings.SplitN(path, "/", 3)
+	if len(elements) < 2 {
+		return path
+	}
+	return elements[0] + "/" + elements[1]
+}
+
+// prefetchSecrets concurrently reads all secrets that are read by the given resolve
+// function and returns a secret reader that returns the prefetched secrets. The resolve
+// function is called with a reader that does not read the secrets but only records their
+// paths, so any errors it encounters should be ignored.
+func prefetchSecrets(sr tpl.SecretReader, resolve func(tpl.SecretReader)) *concurrentSecretReader {
+	recorder := &secretPathRecorder{}
+	resolve(recorder)
+
+	reader := newConcurrentSecretReader(sr, secretReadWorkers)
+	reader.Prefetch(recorder.paths)
+	return reader
+}
+
+// secretPathRecorder is a tpl.SecretReader that records the paths of the secrets
+// that are read, without reading them. It is used to determine which secrets to prefetch.
+type secretPathRecorder struct {
+	paths []string
+}
+
+// ReadSecret records the path and returns an empty value.
+func (r *secretPathRecorder) ReadSecret(path string) (string, error) {
+	r.paths = append(r.paths, path)
+	return "", nil
+}
+
 type secretReaderNotAllowed struct{}
 
 func (sr secretReaderNotAllowed) ReadSecret(path string) (string, error) {
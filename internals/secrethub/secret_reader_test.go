@@ -0,0 +1,154 @@
+package secrethub
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+)
+
+// countingSecretReader is a tpl.SecretReader that counts the reads of every path
+// and the maximum number of reads that happened at the same time.
+type countingSecretReader struct {
+	secrets map[string]string
+	delay   time.Duration
+
+	lock          sync.Mutex
+	reads         map[string]int
+	active        int
+	maxConcurrent int
+}
+
+func (sr *countingSecretReader) ReadSecret(path string) (string, error) {
+	sr.lock.Lock()
+	sr.reads[path]++
+	sr.active++
+	if sr.active > sr.maxConcurrent {
+		sr.maxConcurrent = sr.active
+	}
+	sr.lock.Unlock()
+
+	time.Sleep(sr.delay)
+
+	sr.lock.Lock()
+	sr.active--
+	sr.lock.Unlock()
+
+	secret, ok := sr.secrets[path]
+	if !ok {
+		return "", api.ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+func TestConcurrentSecretReader(t *testing.T) {
+	secrets := map[string]string{}
+	var paths []string
+	for _, c := range "abcdefghijklmnopqrstuvwxyz" {
+		path := "namespace/repo/" + string(c)
+		secrets[path] = string(c)
+		// Every path is requested twice.
+		paths = append(paths, path, path)
+	}
+	paths = append(paths, "namespace/repo/missing")
+
+	sr := &countingSecretReader{
+		secrets: secrets,
+		delay:   time.Millisecond,
+		reads:   map[string]int{},
+	}
+	reader := newConcurrentSecretReader(sr, 4)
+
+	reader.Prefetch(paths)
+
+	for path, value := range secrets {
+		assert.Equal(t, sr.reads[path], 1)
+
+		actual, err := reader.ReadSecret(path)
+		assert.OK(t, err)
+		assert.Equal(t, actual, value)
+	}
+
+	_, err := reader.ReadSecret("namespace/repo/missing")
+	assert.Equal(t, err, api.ErrSecretNotFound)
+	assert.Equal(t, sr.reads["namespace/repo/missing"], 1)
+
+	if sr.maxConcurrent > 4 {
+		t.Errorf("expected at most 4 concurrent reads, got %d", sr.maxConcurrent)
+	}
+}
+
+func TestConcurrentSecretReader_Prefetch_FirstOfRepoIsReadFirst(t *testing.T) {
+	var order []string
+	var lock sync.Mutex
+	sr := secretReaderFunc(func(path string) (string, error) {
+		lock.Lock()
+		order = append(order, path)
+		lock.Unlock()
+		return path, nil
+	})
+	reader := newConcurrentSecretReader(sr, 4)
+
+	reader.Prefetch([]string{"a/repo1/1", "a/repo1/2", "a/repo2/1", "a/repo1/3"})
+
+	assert.Equal(t, order[:2], []string{"a/repo1/1", "a/repo2/1"})
+	assert.Equal(t, len(order), 4)
+}
+
+func TestConcurrentSecretReader_Prefetch_SerialAfterFailure(t *testing.T) {
+	errTest := errors.New("test")
+	var order []string
+	sr := secretReaderFunc(func(path string) (string, error) {
+		order = append(order, path)
+		return "", errTest
+	})
+	reader := newConcurrentSecretReader(sr, 4)
+
+	reader.Prefetch([]string{"a/repo/1", "a/repo/2", "a/repo/3"})
+
+	// The order can only be deterministic when the secrets are read serially.
+	assert.Equal(t, order, []string{"a/repo/1", "a/repo/2", "a/repo/3"})
+
+	_, err := reader.ReadSecret("a/repo/2")
+	assert.Equal(t, err, errTest)
+}
+
+func TestPrefetchSecrets(t *testing.T) {
+	sr := &countingSecretReader{
+		secrets: map[string]string{
+			"namespace/repo/a": "a",
+			"namespace/repo/b": "b",
+		},
+		reads: map[string]int{},
+	}
+
+	reader := prefetchSecrets(sr, func(sr tpl.SecretReader) {
+		for _, path := range []string{"namespace/repo/a", "namespace/repo/b", "namespace/repo/a"} {
+			value, err := sr.ReadSecret(path)
+			assert.OK(t, err)
+			assert.Equal(t, value, "")
+		}
+	})
+
+	assert.Equal(t, sr.reads, map[string]int{
+		"namespace/repo/a": 1,
+		"namespace/repo/b": 1,
+	})
+
+	value, err := reader.ReadSecret("namespace/repo/b")
+	assert.OK(t, err)
+	assert.Equal(t, value, "b")
+	assert.Equal(t, sr.reads["namespace/repo/b"], 1)
+}
+
+// secretReaderFunc is a tpl.SecretReader that calls the function to read a secret.
+type secretReaderFunc func(path string) (string, error)
+
+func (f secretReaderFunc) ReadSecret(path string) (string, error) {
+	return f(path)
+}
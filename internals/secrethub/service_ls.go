@@ -2,8 +2,6 @@ package secrethub
 
 import (
 	"fmt"
-	"strings"
-	"text/tabwriter"
 
 	"github.com/secrethub/secrethub-cli/internals/cli/ui"
 	"github.com/secrethub/secrethub-cli/internals/secrethub/command"
@@ -15,6 +13,7 @@ import (
 type ServiceLsCommand struct {
 	repoPath api.RepoPath
 	quiet    bool
+	format   string
 
 	io              ui.IO
 	useTimestamps   bool
@@ -65,6 +64,9 @@ func (cmd *ServiceLsCommand) Register(r command.Registerer) {
 	clause.Arg("repo-path", "The path to the repository to list services for").Required().PlaceHolder(repoPathPlaceHolder).SetValue(&cmd.repoPath)
 	clause.Flag("quiet", "Only print service IDs.").Short('q').BoolVar(&cmd.quiet)
 	registerTimestampFlag(clause).BoolVar(&cmd.useTimestamps)
+	// The fields of the item do not depend on the service, so an empty service is used to document them.
+	emptyItem := cmd.newServiceTable(NewTimeFormatter(false)).item(&api.Service{Credential: &api.Credential{}})
+	registerOutputFormatFlag(clause, emptyItem).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -98,16 +100,20 @@ outer:
 			fmt.Fprintf(cmd.io.Output(), "%s\n", service.ServiceID)
 		}
 	} else {
-		w := tabwriter.NewWriter(cmd.io.Output(), 0, 2, 2, ' ', 0)
-		serviceTable := cmd.newServiceTable(NewTimeFormatter(cmd.useTimestamps))
-
-		fmt.Fprintln(w, strings.Join(serviceTable.header(), "\t"))
+		serviceTable := cmd.newServiceTable(NewTimeFormatter(cmd.useTimestamps || isStructuredFormat(cmd.format)))
+		formatter, err := newListFormatter(cmd.format, cmd.io.Output(), 2, serviceTable.header())
+		if err != nil {
+			return err
+		}
 
 		for _, service := range included {
-			fmt.Fprintln(w, strings.Join(serviceTable.row(service), "\t"))
+			err = formatter.Write(serviceTable.row(service), serviceTable.item(service))
+			if err != nil {
+				return err
+			}
 		}
 
-		err = w.Flush()
+		err = formatter.Flush()
 		if err != nil {
 			return err
 		}
@@ -119,6 +125,8 @@ outer:
 type serviceTable interface {
 	header() []string
 	row(service *api.Service) []string
+	// item returns the json and yaml format of the service.
+	item(service *api.Service) interface{}
 }
 
 type baseServiceTable struct {
@@ -126,8 +134,8 @@ type baseServiceTable struct {
 }
 
 func (sw baseServiceTable) header(content ...string) []string {
-	res := append([]string{"ID", "DESCRIPTION"}, content...)
-	return append(res, "CREATED")
+	res := append([]string{"id", "description"}, content...)
+	return append(res, "created")
 }
 
 func (sw baseServiceTable) row(service *api.Service, content ...string) []string {
@@ -144,13 +152,30 @@ type keyServiceTable struct {
 }
 
 func (sw keyServiceTable) header() []string {
-	return sw.baseServiceTable.header("TYPE")
+	return sw.baseServiceTable.header("type")
 }
 
 func (sw keyServiceTable) row(service *api.Service) []string {
 	return sw.baseServiceTable.row(service, string(service.Credential.Type))
 }
 
+// keyServiceItem is the json and yaml format of a service listed by service ls.
+type keyServiceItem struct {
+	ID          string `json:"ID"`
+	Description string `json:"Description"`
+	Type        string `json:"Type"`
+	Created     string `json:"Created"`
+}
+
+func (sw keyServiceTable) item(service *api.Service) interface{} {
+	return keyServiceItem{
+		ID:          service.ServiceID,
+		Description: service.Description,
+		Type:        string(service.Credential.Type),
+		Created:     sw.timeFormatter.Format(service.CreatedAt.Local()),
+	}
+}
+
 func newAWSServiceTable(timeFormatter TimeFormatter) serviceTable {
 	return awsServiceTable{baseServiceTable{timeFormatter: timeFormatter}}
 }
@@ -160,13 +185,32 @@ type awsServiceTable struct {
 }
 
 func (sw awsServiceTable) header() []string {
-	return sw.baseServiceTable.header("ROLE", "KMS-KEY")
+	return sw.baseServiceTable.header("role", "kms-key")
 }
 
 func (sw awsServiceTable) row(service *api.Service) []string {
 	return sw.baseServiceTable.row(service, service.Credential.Metadata[api.CredentialMetadataAWSRole], service.Credential.Metadata[api.CredentialMetadataAWSKMSKey])
 }
 
+// awsServiceItem is the json and yaml format of a service listed by service aws ls.
+type awsServiceItem struct {
+	ID          string `json:"ID"`
+	Description string `json:"Description"`
+	Role        string `json:"Role"`
+	KMSKey      string `json:"KMSKey"`
+	Created     string `json:"Created"`
+}
+
+func (sw awsServiceTable) item(service *api.Service) interface{} {
+	return awsServiceItem{
+		ID:          service.ServiceID,
+		Description: service.Description,
+		Role:        service.Credential.Metadata[api.CredentialMetadataAWSRole],
+		KMSKey:      service.Credential.Metadata[api.CredentialMetadataAWSKMSKey],
+		Created:     sw.timeFormatter.Format(service.CreatedAt.Local()),
+	}
+}
+
 func isAWSService(service *api.Service) bool {
 	if service == nil {
 		return false
@@ -184,13 +228,32 @@ func newGCPServiceTable(timeFormatter TimeFormatter) serviceTable {
 }
 
 func (sw gcpServiceTable) header() []string {
-	return sw.baseServiceTable.header("SERVICE-ACCOUNT-EMAIL", "KMS-KEY")
+	return sw.baseServiceTable.header("service-account-email", "kms-key")
 }
 
 func (sw gcpServiceTable) row(service *api.Service) []string {
 	return sw.baseServiceTable.row(service, service.Credential.Metadata[api.CredentialMetadataGCPServiceAccountEmail], service.Credential.Metadata[api.CredentialMetadataGCPKMSKeyResourceID])
 }
 
+// gcpServiceItem is the json and yaml format of a service listed by service gcp ls.
+type gcpServiceItem struct {
+	ID                  string `json:"ID"`
+	Description         string `json:"Description"`
+	ServiceAccountEmail string `json:"ServiceAccountEmail"`
+	KMSKey              string `json:"KMSKey"`
+	Created             string `json:"Created"`
+}
+
+func (sw gcpServiceTable) item(service *api.Service) interface{} {
+	return gcpServiceItem{
+		ID:                  service.ServiceID,
+		Description:         service.Description,
+		ServiceAccountEmail: service.Credential.Metadata[api.CredentialMetadataGCPServiceAccountEmail],
+		KMSKey:              service.Credential.Metadata[api.CredentialMetadataGCPKMSKeyResourceID],
+		Created:             sw.timeFormatter.Format(service.CreatedAt.Local()),
+	}
+}
+
 func isGCPService(service *api.Service) bool {
 	if service == nil {
 		return false
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 
 	"github.com/secrethub/secrethub-go/internals/api"
 
@@ -65,27 +66,32 @@ func (cmd *SetCommand) Run() error {
 		return err
 	}
 
-	client, err := cmd.newClient()
-	if err != nil {
-		return err
+	sources := presenter.Sources()
+	if len(sources) == 0 {
+		return ErrNoSourcesInSpec
 	}
 
-	paths := presenter.Sources()
-	if len(paths) == 0 {
-		return ErrNoSourcesInSpec
+	// Read the secrets in a fixed order, so that the same error is reported on every run.
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
 
 	for _, c := range presenter.EmptyConsumables() {
 		fmt.Fprintf(cmd.io.Output(), "Warning: %s contains no secret declarations.\n", c)
 	}
 
+	secretReader := newConcurrentSecretReader(newSecretReader(cmd.newClient), secretReadWorkers)
+	secretReader.Prefetch(paths)
+
 	secrets := make(map[string]api.SecretVersion)
-	for path := range paths {
-		secret, err := client.Secrets().Versions().GetWithData(path)
+	for _, path := range paths {
+		data, err := secretReader.ReadSecret(path)
 		if err != nil {
 			return err
 		}
-		secrets[path] = *secret
+		secrets[path] = api.SecretVersion{Data: []byte(data)}
 	}
 
 	fmt.Fprintln(cmd.io.Output(), "Setting secrets...")
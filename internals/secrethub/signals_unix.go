@@ -0,0 +1,47 @@
+// +build !windows
+
+package secrethub
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// signalsByName contains the signals that can be sent to a child process by name.
+var signalsByName = map[string]os.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"TERM": syscall.SIGTERM,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+}
+
+// terminateSignal is the signal that is sent to the command to ask it to exit before it is restarted.
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// unforwardedSignals contains the signals received by secrethub that are not forwarded to the child process.
+// SIGCHLD reports state changes of the child process itself, SIGURG is used by the Go runtime to preempt
+// goroutines and SIGPIPE is caused by secrethub writing to a closed pipe.
+var unforwardedSignals = map[os.Signal]bool{
+	syscall.SIGCHLD: true,
+	syscall.SIGURG:  true,
+	syscall.SIGPIPE: true,
+}
+
+// raise terminates secrethub with the given signal, if it is one that terminates a Go program by default.
+// For other signals, e.g. SIGQUIT, the Go runtime would dump the stacks of all goroutines, so it returns without
+// raising the signal.
+func raise(sig syscall.Signal) {
+	switch sig {
+	case syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM:
+		signal.Reset(sig)
+		_ = syscall.Kill(os.Getpid(), sig)
+	}
+}
+
+// isWindowChangeSignal returns whether the signal reports that the size of the terminal has changed.
+func isWindowChangeSignal(sig os.Signal) bool {
+	return sig == syscall.SIGWINCH
+}
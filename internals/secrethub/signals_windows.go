@@ -0,0 +1,31 @@
+// +build windows
+
+package secrethub
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalsByName contains the signals that can be sent to a child process by name.
+var signalsByName = map[string]os.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"TERM": syscall.SIGTERM,
+}
+
+// terminateSignal is nil, because signals cannot be sent to processes on Windows.
+// A command that is restarted is killed right away instead.
+var terminateSignal os.Signal
+
+// unforwardedSignals contains the signals received by secrethub that are not forwarded to the child process.
+var unforwardedSignals = map[os.Signal]bool{}
+
+// raise is a no-op on Windows, where processes cannot be terminated by a signal.
+func raise(sig syscall.Signal) {}
+
+// isWindowChangeSignal returns false, because Windows does not signal changes of the terminal size.
+func isWindowChangeSignal(sig os.Signal) bool {
+	return false
+}
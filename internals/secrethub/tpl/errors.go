@@ -2,6 +2,7 @@ package tpl
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Evaluate errors
@@ -73,3 +74,33 @@ func ErrVariableTagNotClosed(lineNo, colNo int) error {
 		msg:    "expected the closing of a variable tag `}`, but reached the end of the template.",
 	}
 }
+
+// ErrMissingFilterName is returned when a pipe in a secret tag is not followed by the name of a filter.
+func ErrMissingFilterName(lineNo, colNo int, char rune) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "missing_filter_name",
+		msg:    fmt.Sprintf("unexpected '%c', expected the name of a filter after '|'.", char),
+	}
+}
+
+// ErrUnknownFilter is returned when a secret tag uses a filter that does not exist.
+func ErrUnknownFilter(lineNo, colNo int, name string) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "unknown_filter",
+		msg:    fmt.Sprintf("unknown filter '%s'. Available filters are %s.", name, strings.Join(filterNames(), ", ")),
+	}
+}
+
+// ErrFilterArgumentCount is returned when a filter is given the wrong number of arguments.
+func ErrFilterArgumentCount(lineNo, colNo int, name string, expected, actual int) error {
+	return templateSyntaxError{
+		lineNo: lineNo,
+		colNo:  colNo,
+		code:   "filter_argument_count",
+		msg:    fmt.Sprintf("filter '%s' takes %d argument(s), but %d were given.", name, expected, actual),
+	}
+}
@@ -0,0 +1,89 @@
+package tpl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// filterFunc transforms the value of a secret using the arguments given to the filter.
+type filterFunc func(value string, args []string) (string, error)
+
+// filterDefinition describes a built-in filter.
+type filterDefinition struct {
+	args  int
+	apply filterFunc
+}
+
+// filterDefault is the name of the filter that provides a fallback value
+// for secrets that are empty or do not exist.
+const filterDefault = "default"
+
+// builtinFilters contains all filters that can be used in a secret tag.
+var builtinFilters = map[string]filterDefinition{
+	"base64": {
+		apply: func(value string, _ []string) (string, error) {
+			return base64.StdEncoding.EncodeToString([]byte(value)), nil
+		},
+	},
+	"base64url": {
+		apply: func(value string, _ []string) (string, error) {
+			return base64.URLEncoding.EncodeToString([]byte(value)), nil
+		},
+	},
+	filterDefault: {
+		args: 1,
+		apply: func(value string, args []string) (string, error) {
+			if value == "" {
+				return args[0], nil
+			}
+			return value, nil
+		},
+	},
+	"json": {
+		apply: func(value string, _ []string) (string, error) {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return "", err
+			}
+			return string(encoded), nil
+		},
+	},
+	"lower": {
+		apply: func(value string, _ []string) (string, error) {
+			return strings.ToLower(value), nil
+		},
+	},
+	"trim": {
+		apply: func(value string, _ []string) (string, error) {
+			return strings.TrimSpace(value), nil
+		},
+	},
+	"upper": {
+		apply: func(value string, _ []string) (string, error) {
+			return strings.ToUpper(value), nil
+		},
+	},
+}
+
+// filterNames returns the sorted names of all built-in filters.
+func filterNames() []string {
+	names := make([]string, 0, len(builtinFilters))
+	for name := range builtinFilters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// filter is a single step in the pipeline of a secret tag, e.g. `default "x"`.
+type filter struct {
+	name string
+	args []string
+}
+
+// apply transforms the given value with the filter.
+func (f filter) apply(value string) (string, error) {
+	return builtinFilters[f.name].apply(value, f.args)
+}
@@ -7,6 +7,11 @@ var (
 	RBracket  = '}'
 	Backslash = '\\'
 
+	// Pipe and Quote are only used within secret tags and can therefore
+	// not be escaped outside of them.
+	Pipe  = '|'
+	Quote = '"'
+
 	tokens = []rune{Dollar, LBracket, RBracket, Backslash}
 )
 
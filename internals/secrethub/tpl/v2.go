@@ -7,6 +7,8 @@ import (
 	"unicode"
 
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl/internal/token"
+
+	"github.com/secrethub/secrethub-go/internals/api"
 )
 
 // NewV2Parser returns a parser for the v2 template syntax.
@@ -23,6 +25,13 @@ import (
 // Spaces directly after opening delimiters (`{{` and `${`) and directly
 // before closing delimiters (`}}`, `}`) are ignored. They are not
 // included in the secret pahts and variable names.
+//
+// A specific version of a secret can be used by appending it to the path:
+// {{ path/to/secret:3 }}
+//
+// The value of a secret can be transformed by piping it through filters:
+// {{ path/to/secret | trim | base64 }}
+// {{ path/to/secret | default "fallback" }}
 func NewV2Parser() Parser {
 	return parserV2{}
 }
@@ -41,7 +50,8 @@ type node interface {
 }
 
 type secret struct {
-	path []node
+	path    []node
+	filters []filter
 }
 
 func (s secret) evaluate(ctx context) (string, error) {
@@ -54,7 +64,33 @@ func (s secret) evaluate(ctx context) (string, error) {
 
 		buffer.WriteString(eval)
 	}
-	return ctx.secret(buffer.String())
+
+	value, err := ctx.secret(buffer.String())
+	if api.IsErrNotFound(err) && s.hasDefault() {
+		value, err = "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range s.filters {
+		value, err = f.apply(value)
+		if err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// hasDefault returns whether the secret has a default filter, in which case
+// a secret that does not exist is treated as an empty value.
+func (s secret) hasDefault() bool {
+	for _, f := range s.filters {
+		if f.name == filterDefault {
+			return true
+		}
+	}
+	return false
 }
 
 type variable struct {
@@ -95,6 +131,9 @@ type parserV2 struct{}
 // - Variable tags cannot contain secret tags.
 // - Secret tags cannot contain secret tags (they cannot be nested).
 // - Variable tags cannot contain variable tags (they cannot be nested).
+// - Secret tags can end with a pipeline of filters, separated by pipes:
+//   `{{ path/to/secret | trim | default "x" }}`. Filter arguments are double
+//   quoted strings in which `\"` and `\\` can be used to escape characters.
 func (p parserV2) Parse(raw string, line, column int) (Template, error) {
 	parser := newV2Parser(bytes.NewBufferString(raw), line, column)
 
@@ -360,6 +399,23 @@ func (p *v2Parser) parseSecret() (node, error) {
 				return nil, checkError(err)
 			}
 
+			if p.next == token.Pipe {
+				err = p.readRune()
+				if err != nil {
+					return nil, checkError(err)
+				}
+
+				filters, err := p.parseFilters()
+				if err != nil {
+					return nil, checkError(err)
+				}
+
+				return secret{
+					path:    path,
+					filters: filters,
+				}, nil
+			}
+
 			if p.next != token.RBracket {
 				return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
 			}
@@ -378,6 +434,18 @@ func (p *v2Parser) parseSecret() (node, error) {
 			}, nil
 		}
 
+		if p.current == token.Pipe {
+			filters, err := p.parseFilters()
+			if err != nil {
+				return nil, checkError(err)
+			}
+
+			return secret{
+				path:    path,
+				filters: filters,
+			}, nil
+		}
+
 		if p.current == token.RBracket {
 			if p.next == token.RBracket {
 				return secret{
@@ -396,6 +464,127 @@ func (p *v2Parser) parseSecret() (node, error) {
 	}
 }
 
+// parseFilters parses the pipeline of filters at the end of a secret tag up to the
+// closing delimiter. The current character should be the first pipe ('|') when
+// parseFilters is called.
+//
+// When parseFilters returns, the next character in the buffer is the last character
+// of the closing delimiter of the secret tag ('}').
+func (p *v2Parser) parseFilters() ([]filter, error) {
+	filters := []filter{}
+
+	for {
+		err := p.skipWhiteSpace()
+		if err != nil {
+			return nil, err
+		}
+
+		lineNo, colNo := p.lineNo, p.columnNo+1
+
+		var name bytes.Buffer
+		for p.isVariableRune(p.next) {
+			name.WriteRune(p.next)
+
+			err := p.readRune()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if name.Len() == 0 {
+			return nil, ErrMissingFilterName(p.lineNo, p.columnNo+1, p.next)
+		}
+
+		f := filter{
+			name: name.String(),
+			args: []string{},
+		}
+
+		for {
+			err := p.skipWhiteSpace()
+			if err != nil {
+				return nil, err
+			}
+
+			if p.next != token.Quote {
+				break
+			}
+
+			arg, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			f.args = append(f.args, arg)
+		}
+
+		definition, ok := builtinFilters[f.name]
+		if !ok {
+			return nil, ErrUnknownFilter(lineNo, colNo, f.name)
+		}
+		if len(f.args) != definition.args {
+			return nil, ErrFilterArgumentCount(lineNo, colNo, f.name, definition.args, len(f.args))
+		}
+
+		filters = append(filters, f)
+
+		if p.next == token.Pipe {
+			err := p.readRune()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if p.next != token.RBracket {
+			return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
+		}
+
+		err = p.readRune()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next != token.RBracket {
+			return nil, ErrUnexpectedCharacter(p.lineNo, p.columnNo+1, p.next, token.RBracket)
+		}
+
+		return filters, nil
+	}
+}
+
+// parseString parses a double quoted filter argument. The next character should
+// be the opening quote when parseString is called. When parseString returns, the
+// current character is the closing quote.
+func (p *v2Parser) parseString() (string, error) {
+	var buffer bytes.Buffer
+
+	err := p.readRune()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		err := p.readRune()
+		if err != nil {
+			return "", err
+		}
+
+		if p.current == token.Backslash && (p.next == token.Quote || p.next == token.Backslash) {
+			err := p.readRune()
+			if err != nil {
+				return "", err
+			}
+			buffer.WriteRune(p.current)
+			continue
+		}
+
+		if p.current == token.Quote {
+			return buffer.String(), nil
+		}
+
+		buffer.WriteRune(p.current)
+	}
+}
+
 // isSecretPathRune returns whether the given rune is allowed to be used in
 // a secret path.
 func (p v2Parser) isSecretPathRune(r rune) bool {
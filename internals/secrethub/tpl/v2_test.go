@@ -7,6 +7,7 @@ import (
 
 	"github.com/secrethub/secrethub-cli/internals/secrethub/tpl/fakes"
 
+	"github.com/secrethub/secrethub-go/internals/api"
 	"github.com/secrethub/secrethub-go/internals/assert"
 )
 
@@ -592,6 +593,78 @@ func TestParserV2_parse(t *testing.T) {
 			input: "{{ foo/bar }baz",
 			err:   ErrUnexpectedCharacter(1, 13, 'b', '}'),
 		},
+		"secret with filter": {
+			input: "{{ a | base64 }}",
+			expected: []node{
+				secret{
+					path: []node{
+						character('a'),
+					},
+					filters: []filter{
+						{name: "base64", args: []string{}},
+					},
+				},
+			},
+		},
+		"secret with filter without spaces": {
+			input: "{{a|trim}}",
+			expected: []node{
+				secret{
+					path: []node{
+						character('a'),
+					},
+					filters: []filter{
+						{name: "trim", args: []string{}},
+					},
+				},
+			},
+		},
+		"secret with filter pipeline and arguments": {
+			input: `{{ ${var}:3 | default "x \"y\" \\" | json }}`,
+			expected: []node{
+				secret{
+					path: []node{
+						variable{
+							key: "var",
+						},
+						character(':'),
+						character('3'),
+					},
+					filters: []filter{
+						{name: "default", args: []string{`x "y" \`}},
+						{name: "json", args: []string{}},
+					},
+				},
+			},
+		},
+		"unknown filter": {
+			input: "{{ a | foo }}",
+			err:   ErrUnknownFilter(1, 8, "foo"),
+		},
+		"unknown filter on second line": {
+			input: "x\n{{ a |\tfoo }}",
+			err:   ErrUnknownFilter(2, 8, "foo"),
+		},
+		"filter without argument": {
+			input: "{{ a | default }}",
+			err:   ErrFilterArgumentCount(1, 8, "default", 1, 0),
+		},
+		"filter with too many arguments": {
+			input: `{{ a | trim "x" }}`,
+			err:   ErrFilterArgumentCount(1, 8, "trim", 0, 1),
+		},
+		"missing filter name": {
+			input: "{{ a | }}",
+			err:   ErrMissingFilterName(1, 8, '}'),
+		},
+		"filter not followed by closing delimiter": {
+			input: "{{ a | trim foo }}",
+			err:   ErrUnexpectedCharacter(1, 13, 'f', '}'),
+		},
+		"filter argument not closed": {
+			input: `{{ a | default "x }}`,
+			err:   ErrSecretTagNotClosed(1, 21),
+		},
 		"variable tag not closed": {
 			input: "${ var",
 			err:   ErrVariableTagNotClosed(1, 7),
@@ -662,6 +735,65 @@ func TestV2(t *testing.T) {
 			},
 			expected: "hello world",
 		},
+		"secret version": {
+			raw: "{{ path:3 }}",
+			secrets: map[string]string{
+				"path:3": "foo",
+			},
+			expected: "foo",
+		},
+		"base64 filter": {
+			raw: "{{ path | base64 }}",
+			secrets: map[string]string{
+				"path": "foo?",
+			},
+			expected: "Zm9vPw==",
+		},
+		"json filter": {
+			raw: `{"password": {{ path | json }}}`,
+			secrets: map[string]string{
+				"path": `p"a\ss`,
+			},
+			expected: `{"password": "p\"a\\ss"}`,
+		},
+		"trim filter": {
+			raw: "{{ path | trim }}",
+			secrets: map[string]string{
+				"path": " foo\n",
+			},
+			expected: "foo",
+		},
+		"filters are applied in order": {
+			raw: "{{ path | trim | upper | base64 }}",
+			secrets: map[string]string{
+				"path": " foo\n",
+			},
+			expected: "Rk9P",
+		},
+		"default filter with missing secret": {
+			raw:      `{{ path | default "bar" }}`,
+			secrets:  map[string]string{},
+			expected: "bar",
+		},
+		"default filter with empty secret": {
+			raw: `{{ path | default "bar" }}`,
+			secrets: map[string]string{
+				"path": "",
+			},
+			expected: "bar",
+		},
+		"default filter with existing secret": {
+			raw: `{{ path | default "bar" }}`,
+			secrets: map[string]string{
+				"path": "foo",
+			},
+			expected: "foo",
+		},
+		"missing secret without default": {
+			raw:     "{{ path | trim }}",
+			secrets: map[string]string{},
+			evalErr: api.ErrSecretNotFound,
+		},
 		"missing var": {
 			raw:  "hello {{ ${app}/greeting }}",
 			vars: map[string]string{},
@@ -18,6 +18,7 @@ type TreeCommand struct {
 	fullPaths     bool
 	noIndentation bool
 	noReport      bool
+	format        string
 	newClient     newClientFunc
 }
 
@@ -41,6 +42,12 @@ func (cmd *TreeCommand) Run() error {
 		return err
 	}
 
+	if isStructuredFormat(cmd.format) {
+		return printStructured(cmd.io.Output(), cmd.format, newTreeDirOutput(t.RootDir, cmd.path.Value()))
+	} else if cmd.format != formatTable && cmd.format != "" {
+		return ErrInvalidOutputFormat(cmd.format)
+	}
+
 	cmd.printTree(t, cmd.io.Output())
 	return nil
 }
@@ -54,6 +61,7 @@ func (cmd *TreeCommand) Register(r command.Registerer) {
 	clause.Flag("no-indentation", "Don't print indentation lines.").Short('i').BoolVar(&cmd.noIndentation)
 	clause.Flag("no-report", "Turn off secret/directory count at end of tree listing.").BoolVar(&cmd.noReport)
 	clause.Flag("noreport", "Turn off secret/directory count at end of tree listing.").Hidden().BoolVar(&cmd.noReport)
+	clause.Flag("output-format", "Specify the format in which to output the tree. Options are: table, json and yaml. The table format prints a tree-like listing.").HintOptions(formatTable, formatJSON, formatYAML).Default(formatTable).StringVar(&cmd.format)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -138,3 +146,44 @@ func (cmd *TreeCommand) printDirContentsRecursively(dir *api.Dir, prefix string,
 		i++
 	}
 }
+
+// treeDirOutput is the printable format of a directory in a tree.
+type treeDirOutput struct {
+	Name    string
+	Path    string
+	Status  string
+	Dirs    []treeDirOutput
+	Secrets []treeSecretOutput
+}
+
+// treeSecretOutput is the printable format of a secret in a tree.
+type treeSecretOutput struct {
+	Name   string
+	Path   string
+	Status string
+}
+
+// newTreeDirOutput returns the printable format of a directory at the given path and all its contents.
+func newTreeDirOutput(dir *api.Dir, path string) treeDirOutput {
+	sort.Sort(api.SortDirByName(dir.SubDirs))
+	sort.Sort(api.SortSecretByName(dir.Secrets))
+
+	out := treeDirOutput{
+		Name:    dir.Name,
+		Path:    path,
+		Status:  dir.Status,
+		Dirs:    make([]treeDirOutput, len(dir.SubDirs)),
+		Secrets: make([]treeSecretOutput, len(dir.Secrets)),
+	}
+	for i, sub := range dir.SubDirs {
+		out.Dirs[i] = newTreeDirOutput(sub, path+"/"+sub.Name)
+	}
+	for i, secret := range dir.Secrets {
+		out.Secrets[i] = treeSecretOutput{
+			Name:   secret.Name,
+			Path:   path + "/" + secret.Name,
+			Status: secret.Status,
+		}
+	}
+	return out
+}
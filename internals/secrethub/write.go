@@ -17,16 +17,23 @@ var (
 	errEmptySecret                     = errMain.Code("cannot_write_empty_secret").Error("secret is empty or contains only whitespace")
 	errClipAndInFile                   = errMain.Code("clip_and_in_file").Error("clip and in-file cannot be used together")
 	errMultilineWithNonInteractiveFlag = errMain.Code("multiline_flag_conflict").Error("multiline cannot be used together with clip or in-file")
+	errDryRunWithoutFile               = errMain.Code("dry_run_without_file").Error("dry-run can only be used together with from-env-file, from-json or from-yaml")
 )
 
 // WriteCommand is a command to write content to a secret.
 type WriteCommand struct {
 	io           ui.IO
-	path         api.SecretPath
+	path         api.Path
 	inFile       string
 	multiline    bool
 	useClipboard bool
 	noTrim       bool
+	fromEnvFile  string
+	fromJSON     string
+	fromYAML     string
+	nameCase     string
+	onExisting   string
+	dryRun       bool
 	clipper      clip.Clipper
 	newClient    newClientFunc
 }
@@ -42,12 +49,18 @@ func NewWriteCommand(io ui.IO, newClient newClientFunc) *WriteCommand {
 
 // Register registers the command, arguments and flags on the provided Registerer.
 func (cmd *WriteCommand) Register(r command.Registerer) {
-	clause := r.Command("write", "Write a secret.")
-	clause.Arg("secret-path", "The path to the secret").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.path)
+	clause := r.Command("write", "Write a secret, or write all keys in a file as secrets in a directory.")
+	clause.Arg("secret-path", "The path to the secret, or to the directory to write the secrets to when writing a file with --from-env-file, --from-json or --from-yaml").Required().PlaceHolder(secretPathPlaceHolder).SetValue(&cmd.path)
 	clause.Flag("clip", "Use clipboard content as input.").Short('c').BoolVar(&cmd.useClipboard)
 	clause.Flag("multiline", "Prompt for multiple lines of input, until an EOF is reached. On Linux/Mac, press CTRL-D to end input. On Windows, press CTRL-Z and then ENTER to end input.").Short('m').BoolVar(&cmd.multiline)
 	clause.Flag("no-trim", "Do not trim leading and trailing whitespace in the secret.").BoolVar(&cmd.noTrim)
 	clause.Flag("in-file", "Use the contents of this file as the value of the secret.").Short('i').StringVar(&cmd.inFile)
+	clause.Flag("from-env-file", "Write every key=value pair in this .env file as a secret in the given directory.").StringVar(&cmd.fromEnvFile)
+	clause.Flag("from-json", "Write every key in this JSON file as a secret in the given directory. Keys of nested objects are written to subdirectories.").StringVar(&cmd.fromJSON)
+	clause.Flag("from-yaml", "Write every key in this YAML file as a secret in the given directory. Keys of nested mappings are written to subdirectories.").StringVar(&cmd.fromYAML)
+	clause.Flag("name-case", "How to convert the keys in the file to secret names. Options are keep, lower, upper, snake (lowercase words joined by underscores) and kebab (lowercase words joined by dashes). For snake and kebab, keys are split into words on dashes, underscores and case changes, e.g. dbPassword becomes db_password.").HintOptions(nameCaseKeep, nameCaseLower, nameCaseUpper, nameCaseSnake, nameCaseKebab).Default(nameCaseKeep).StringVar(&cmd.nameCase)
+	clause.Flag("on-existing", "What to do when a key in the file is written to a secret that already exists. Options are fail, skip and overwrite. Overwriting a secret writes a new version of it.").HintOptions(onExistingFail, onExistingSkip, onExistingOverwrite).Default(onExistingFail).StringVar(&cmd.onExisting)
+	registerDryRunFlag(clause).BoolVar(&cmd.dryRun)
 
 	command.BindAction(clause, cmd.Run)
 }
@@ -56,6 +69,10 @@ func (cmd *WriteCommand) Register(r command.Registerer) {
 func (cmd *WriteCommand) Run() error {
 	var err error
 
+	if cmd.fromEnvFile != "" || cmd.fromJSON != "" || cmd.fromYAML != "" {
+		return cmd.runBulk()
+	}
+
 	// This error is checked here to fail fast.
 	// The error is also checked in the client.
 	// Without this check here, the user would be prompted for input when io.Stdin is not piped, but the path is incorrect.
@@ -71,6 +88,15 @@ func (cmd *WriteCommand) Run() error {
 		return errClipAndInFile
 	}
 
+	if cmd.dryRun {
+		return errDryRunWithoutFile
+	}
+
+	secretPath, err := cmd.path.ToSecretPath()
+	if err != nil {
+		return err
+	}
+
 	var data []byte
 	if cmd.useClipboard {
 		data, err = cmd.clipper.ReadAll()
@@ -120,12 +146,12 @@ func (cmd *WriteCommand) Run() error {
 		return err
 	}
 
-	version, err := client.Secrets().Write(cmd.path.Value(), data)
+	version, err := client.Secrets().Write(secretPath.Value(), data)
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(cmd.io.Output(), "Write complete! The given value has been written to %s:%d\n", cmd.path, version.Version)
+	_, err = fmt.Fprintf(cmd.io.Output(), "Write complete! The given value has been written to %s:%d\n", secretPath, version.Version)
 	if err != nil {
 		return err
 	}
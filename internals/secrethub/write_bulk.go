@@ -0,0 +1,352 @@
+package secrethub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Errors
+var (
+	ErrInvalidNameCase     = errMain.Code("invalid_name_case").ErrorPref("invalid name case: %s. Options are keep, lower, upper, snake and kebab")
+	ErrInvalidOnExisting   = errMain.Code("invalid_on_existing").ErrorPref("invalid value for --on-existing: %s. Options are fail, skip and overwrite")
+	ErrInvalidBulkKey      = errMain.Code("invalid_bulk_key").ErrorPref("key %s in %s cannot be used as a secret name: %s")
+	ErrBulkKeyCollision    = errMain.Code("bulk_key_collision").ErrorPref("keys %s and %s in %s are both written to the secret %s")
+	ErrEmptyBulkValue      = errMain.Code("empty_bulk_value").ErrorPref("the value of key %s in %s is empty")
+	ErrUnsupportedBulkType = errMain.Code("unsupported_bulk_type").ErrorPref("the value of key %s is a %T, only strings, numbers, booleans and nested objects can be written")
+	ErrBulkSecretsExist    = errMain.Code("bulk_secrets_exist").ErrorPref("%s already exist, for example %s. Use --on-existing=skip to only write new secrets or --on-existing=overwrite to write a new version of the existing secrets")
+)
+
+const (
+	nameCaseKeep  = "keep"
+	nameCaseLower = "lower"
+	nameCaseUpper = "upper"
+	nameCaseSnake = "snake"
+	nameCaseKebab = "kebab"
+
+	onExistingFail      = "fail"
+	onExistingSkip      = "skip"
+	onExistingOverwrite = "overwrite"
+)
+
+// bulkSecret is a key-value pair from a file that is written as a secret.
+type bulkSecret struct {
+	// key is the key in the file. Nested keys are joined with a slash.
+	key   string
+	value string
+	path  api.SecretPath
+}
+
+// runBulk writes every key in the file given with --from-env-file, --from-json or
+// --from-yaml as a secret in the directory given as argument.
+func (cmd *WriteCommand) runBulk() error {
+	if cmd.useClipboard || cmd.inFile != "" || cmd.multiline {
+		return ErrFlagsConflict("--from-env-file, --from-json or --from-yaml and --clip, --in-file or --multiline")
+	}
+
+	var file string
+	var parse func([]byte) ([]bulkSecret, error)
+	var count int
+	if cmd.fromEnvFile != "" {
+		file, parse = cmd.fromEnvFile, parseBulkDotEnv
+		count++
+	}
+	if cmd.fromJSON != "" {
+		file, parse = cmd.fromJSON, parseBulkJSON
+		count++
+	}
+	if cmd.fromYAML != "" {
+		file, parse = cmd.fromYAML, parseBulkYAML
+		count++
+	}
+	if count > 1 {
+		return ErrFlagsConflict("--from-env-file, --from-json and --from-yaml")
+	}
+
+	if cmd.onExisting != onExistingFail && cmd.onExisting != onExistingSkip && cmd.onExisting != onExistingOverwrite {
+		return ErrInvalidOnExisting(cmd.onExisting)
+	}
+
+	dirPath, err := cmd.path.ToDirPath()
+	if err != nil {
+		return err
+	}
+
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ErrReadFile(file, err)
+	}
+
+	secrets, err := parse(raw)
+	if err != nil {
+		return ErrReadFile(file, err)
+	}
+
+	// The keys are sorted to report collisions in a deterministic order.
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].key < secrets[j].key
+	})
+
+	written := map[api.SecretPath]string{}
+	for i, secret := range secrets {
+		if secret.value == "" {
+			return ErrEmptyBulkValue(secret.key, file)
+		}
+
+		names := strings.Split(secret.key, "/")
+		for j, name := range names {
+			names[j], err = transformNameCase(name, cmd.nameCase)
+			if err != nil {
+				return err
+			}
+
+			err = api.ValidateSecretName(names[j])
+			if err != nil {
+				return ErrInvalidBulkKey(secret.key, file, err)
+			}
+		}
+
+		secrets[i].path = dirPath.JoinSecret(strings.Join(names, "/"))
+		if key, ok := written[secrets[i].path]; ok {
+			return ErrBulkKeyCollision(key, secret.key, file, secrets[i].path)
+		}
+		written[secrets[i].path] = secret.key
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].path < secrets[j].path
+	})
+
+	client, err := cmd.newClient()
+	if err != nil {
+		return err
+	}
+
+	// The directory itself and all parents of the secrets are created when they do not exist.
+	var dirs []api.DirPath
+	seen := map[api.DirPath]bool{}
+	created := map[api.DirPath]bool{}
+	for _, secret := range secrets {
+		dir := dirPath
+		relDirs := strings.Split(strings.TrimPrefix(secret.path.Value(), dirPath.Value()+"/"), "/")
+		relDirs = relDirs[:len(relDirs)-1]
+		for i := -1; i < len(relDirs); i++ {
+			if i >= 0 {
+				dir = dir.JoinDir(relDirs[i])
+			}
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+
+			exists, err := client.Dirs().Exists(dir.Value())
+			if err != nil {
+				return err
+			}
+			if !exists {
+				dirs = append(dirs, dir)
+				created[dir] = true
+			}
+		}
+	}
+
+	var existing []api.SecretPath
+	skipped := map[api.SecretPath]bool{}
+	for _, secret := range secrets {
+		parent, err := secret.path.GetParentPath()
+		if err != nil {
+			return err
+		}
+		// Secrets in directories that do not exist yet cannot exist either.
+		if created[api.DirPath(parent)] {
+			continue
+		}
+
+		exists, err := client.Secrets().Exists(secret.path.Value())
+		if err != nil {
+			return err
+		}
+		if exists {
+			existing = append(existing, secret.path)
+			skipped[secret.path] = cmd.onExisting == onExistingSkip
+		}
+	}
+
+	if len(existing) > 0 && cmd.onExisting == onExistingFail {
+		return ErrBulkSecretsExist(pluralize("secret", "secrets", len(existing)), existing[0])
+	}
+
+	for _, dir := range dirs {
+		if !cmd.dryRun {
+			_, err = client.Dirs().Create(dir.Value())
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.io.Output(), "Create directory %s\n", dir)
+	}
+
+	count = 0
+	for _, secret := range secrets {
+		if skipped[secret.path] {
+			fmt.Fprintf(cmd.io.Output(), "Skip %s, it already exists\n", secret.path)
+			continue
+		}
+
+		if !cmd.dryRun {
+			_, err = client.Secrets().Write(secret.path.Value(), []byte(secret.value))
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(cmd.io.Output(), "Write %s\n", secret.path)
+		count++
+	}
+
+	if !cmd.dryRun {
+		fmt.Fprintf(cmd.io.Output(), "Write complete! %s been written to %s\n", pluralize("secret has", "secrets have", count), dirPath)
+	}
+	return nil
+}
+
+// transformNameCase converts the name to the given case.
+// For snake and kebab case, the name is split into words as described by splitNameWords.
+func transformNameCase(name string, nameCase string) (string, error) {
+	switch nameCase {
+	case nameCaseKeep:
+		return name, nil
+	case nameCaseLower:
+		return strings.ToLower(name), nil
+	case nameCaseUpper:
+		return strings.ToUpper(name), nil
+	case nameCaseSnake:
+		return strings.ToLower(strings.Join(splitNameWords(name), "_")), nil
+	case nameCaseKebab:
+		return strings.ToLower(strings.Join(splitNameWords(name), "-")), nil
+	default:
+		return "", ErrInvalidNameCase(nameCase)
+	}
+}
+
+// splitNameWords splits the name into words on - and _ and on case boundaries,
+// so that camelCase and PascalCase names are split as well, e.g. dbPassword
+// into db and Password and APIKey into API and Key.
+func splitNameWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '-' || r == '_' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			// An uppercase letter starts a new word after a lowercase letter or digit,
+			// or when it is the last letter of an acronym followed by a lowercase letter.
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextIsLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// parseBulkDotEnv parses the key-value pairs in the .env syntax (key=value).
+func parseBulkDotEnv(raw []byte) ([]bulkSecret, error) {
+	vars, err := parseDotEnv(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]bulkSecret, len(vars))
+	for i, v := range vars {
+		secrets[i] = bulkSecret{
+			key:   v.key,
+			value: v.value,
+		}
+	}
+	return secrets, nil
+}
+
+// parseBulkJSON parses a JSON object. Keys of nested objects are joined with a slash.
+func parseBulkJSON(raw []byte) ([]bulkSecret, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	err := decoder.Decode(&values)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenBulkSecrets("", values)
+}
+
+// parseBulkYAML parses a YAML mapping. Keys of nested mappings are joined with a slash.
+func parseBulkYAML(raw []byte) ([]bulkSecret, error) {
+	var values map[string]interface{}
+	err := yaml.Unmarshal(raw, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenBulkSecrets("", values)
+}
+
+// flattenBulkSecrets returns a secret for every value in the object, prefixing the
+// keys with the given prefix. Nested objects are flattened recursively.
+func flattenBulkSecrets(prefix string, values map[string]interface{}) ([]bulkSecret, error) {
+	var secrets []bulkSecret
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "/" + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			nested, err := flattenBulkSecrets(key, v)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, nested...)
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(v))
+			for k, nestedValue := range v {
+				converted[fmt.Sprint(k)] = nestedValue
+			}
+			nested, err := flattenBulkSecrets(key, converted)
+			if err != nil {
+				return nil, err
+			}
+			secrets = append(secrets, nested...)
+		case string:
+			secrets = append(secrets, bulkSecret{key: key, value: v})
+		case json.Number, int, float64, bool:
+			secrets = append(secrets, bulkSecret{key: key, value: fmt.Sprint(v)})
+		case nil:
+			secrets = append(secrets, bulkSecret{key: key})
+		default:
+			return nil, ErrUnsupportedBulkType(key, v)
+		}
+	}
+	return secrets, nil
+}
@@ -0,0 +1,342 @@
+package secrethub
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/secrethub/secrethub-cli/internals/cli/ui/fakeui"
+
+	"github.com/secrethub/secrethub-go/internals/api"
+	"github.com/secrethub/secrethub-go/internals/assert"
+	"github.com/secrethub/secrethub-go/pkg/secrethub"
+	"github.com/secrethub/secrethub-go/pkg/secrethub/fakeclient"
+)
+
+func TestWriteCommand_runBulk(t *testing.T) {
+	cases := map[string]struct {
+		cmd      WriteCommand
+		file     string
+		content  string
+		existing []string
+		ops      []string
+		out      string
+		err      func(file string) error
+	}{
+		"env file": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseLower,
+			},
+			file:    "app.env",
+			content: "DB_USER=admin\n# comment\nDB_PASSWORD='pass word'\n",
+			ops: []string{
+				"write company/app/db_password=pass word",
+				"write company/app/db_user=admin",
+			},
+			out: "Write company/app/db_password\n" +
+				"Write company/app/db_user\n" +
+				"Write complete! 2 secrets have been written to company/app\n",
+		},
+		"json is flattened": {
+			cmd: WriteCommand{
+				path:     "company/app/config",
+				nameCase: nameCaseKeep,
+			},
+			file:    "app.json",
+			content: `{"db": {"user": "admin", "port": 5432}, "debug": true}`,
+			ops: []string{
+				"mkdir company/app/config",
+				"mkdir company/app/config/db",
+				"write company/app/config/db/port=5432",
+				"write company/app/config/db/user=admin",
+				"write company/app/config/debug=true",
+			},
+			out: "Create directory company/app/config\n" +
+				"Create directory company/app/config/db\n" +
+				"Write company/app/config/db/port\n" +
+				"Write company/app/config/db/user\n" +
+				"Write company/app/config/debug\n" +
+				"Write complete! 3 secrets have been written to company/app/config\n",
+		},
+		"yaml is flattened": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseKebab,
+			},
+			file:    "app.yml",
+			content: "DB:\n  API_KEY: foo\n",
+			ops: []string{
+				"mkdir company/app/db",
+				"write company/app/db/api-key=foo",
+			},
+			out: "Create directory company/app/db\n" +
+				"Write company/app/db/api-key\n" +
+				"Write complete! 1 secret has been written to company/app\n",
+		},
+		"dry run": {
+			cmd: WriteCommand{
+				path:     "company/app/config",
+				nameCase: nameCaseKeep,
+				dryRun:   true,
+			},
+			file:    "app.yml",
+			content: "db:\n  user: admin\n",
+			out: "Create directory company/app/config\n" +
+				"Create directory company/app/config/db\n" +
+				"Write company/app/config/db/user\n",
+		},
+		"existing secrets fail": {
+			cmd: WriteCommand{
+				path:       "company/app",
+				nameCase:   nameCaseKeep,
+				onExisting: onExistingFail,
+			},
+			file:     "app.env",
+			content:  "a=1\nb=2\nc=3\n",
+			existing: []string{"company/app/a", "company/app/c"},
+			err: func(string) error {
+				return ErrBulkSecretsExist("2 secrets", api.SecretPath("company/app/a"))
+			},
+		},
+		"existing secrets skipped": {
+			cmd: WriteCommand{
+				path:       "company/app",
+				nameCase:   nameCaseKeep,
+				onExisting: onExistingSkip,
+			},
+			file:     "app.env",
+			content:  "a=1\nb=2\n",
+			existing: []string{"company/app/a"},
+			ops: []string{
+				"write company/app/b=2",
+			},
+			out: "Skip company/app/a, it already exists\n" +
+				"Write company/app/b\n" +
+				"Write complete! 1 secret has been written to company/app\n",
+		},
+		"existing secrets overwritten": {
+			cmd: WriteCommand{
+				path:       "company/app",
+				nameCase:   nameCaseKeep,
+				onExisting: onExistingOverwrite,
+			},
+			file:     "app.env",
+			content:  "a=1\nb=2\n",
+			existing: []string{"company/app/a"},
+			ops: []string{
+				"write company/app/a=1",
+				"write company/app/b=2",
+			},
+			out: "Write company/app/a\n" +
+				"Write company/app/b\n" +
+				"Write complete! 2 secrets have been written to company/app\n",
+		},
+		"keys collide after case transform": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseLower,
+			},
+			file:    "app.json",
+			content: `{"foo": "1", "FOO": "2"}`,
+			err: func(file string) error {
+				return ErrBulkKeyCollision("FOO", "foo", file, api.SecretPath("company/app/foo"))
+			},
+		},
+		"invalid key": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseKeep,
+			},
+			file:    "app.env",
+			content: "invalid key=1\n",
+			err: func(file string) error {
+				return ErrInvalidBulkKey("invalid key", file, api.ErrInvalidSecretName)
+			},
+		},
+		"empty value": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseKeep,
+			},
+			file:    "app.env",
+			content: "a=\n",
+			err: func(file string) error {
+				return ErrEmptyBulkValue("a", file)
+			},
+		},
+		"list value": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseKeep,
+			},
+			file:    "app.json",
+			content: `{"hosts": ["a", "b"]}`,
+			err: func(file string) error {
+				return ErrReadFile(file, ErrUnsupportedBulkType("hosts", []interface{}{}))
+			},
+		},
+		"camel case json to snake case": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: nameCaseSnake,
+			},
+			file:    "app.json",
+			content: `{"dbPassword": "foo", "ApiKey": "bar"}`,
+			ops: []string{
+				"write company/app/api_key=bar",
+				"write company/app/db_password=foo",
+			},
+			out: "Write company/app/api_key\n" +
+				"Write company/app/db_password\n" +
+				"Write complete! 2 secrets have been written to company/app\n",
+		},
+		"invalid name case": {
+			cmd: WriteCommand{
+				path:     "company/app",
+				nameCase: "camel",
+			},
+			file:    "app.env",
+			content: "a=1\n",
+			err: func(string) error {
+				return ErrInvalidNameCase("camel")
+			},
+		},
+		"in-file": {
+			cmd: WriteCommand{
+				path:   "company/app",
+				inFile: "secret.txt",
+			},
+			file:    "app.env",
+			content: "a=1\n",
+			err: func(string) error {
+				return ErrFlagsConflict("--from-env-file, --from-json or --from-yaml and --clip, --in-file or --multiline")
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := testdata.tempDir(t)
+			defer cleanup()
+
+			writeTestFiles(t, dir, map[string]string{tc.file: tc.content})
+			path := filepath.Join(dir, tc.file)
+			switch filepath.Ext(tc.file) {
+			case ".env":
+				tc.cmd.fromEnvFile = path
+			case ".json":
+				tc.cmd.fromJSON = path
+			case ".yml":
+				tc.cmd.fromYAML = path
+			}
+			if tc.cmd.onExisting == "" {
+				tc.cmd.onExisting = onExistingFail
+			}
+
+			var ops []string
+			io := fakeui.NewIO(t)
+			tc.cmd.io = io
+			tc.cmd.newClient = func() (secrethub.ClientInterface, error) {
+				return fakeclient.Client{
+					DirService: &fakeclient.DirService{
+						ExistsFunc: func(path string) (bool, error) {
+							return path == "company/app", nil
+						},
+						CreateFunc: func(path string) (*api.Dir, error) {
+							ops = append(ops, "mkdir "+path)
+							return &api.Dir{}, nil
+						},
+					},
+					SecretService: &fakeclient.SecretService{
+						ExistsFunc: func(path string) (bool, error) {
+							for _, existing := range tc.existing {
+								if path == existing {
+									return true, nil
+								}
+							}
+							return false, nil
+						},
+						WriteFunc: func(path string, data []byte) (*api.SecretVersion, error) {
+							ops = append(ops, fmt.Sprintf("write %s=%s", path, data))
+							return &api.SecretVersion{}, nil
+						},
+					},
+				}, nil
+			}
+
+			err := tc.cmd.Run()
+
+			var expectedErr error
+			if tc.err != nil {
+				expectedErr = tc.err(path)
+			}
+			assert.Equal(t, err, expectedErr)
+			assert.Equal(t, ops, tc.ops)
+			assert.Equal(t, io.Out.String(), tc.out)
+		})
+	}
+}
+
+func TestTransformNameCase(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		nameCase string
+		expected string
+	}{
+		"snake camelCase": {
+			name:     "dbPassword",
+			nameCase: nameCaseSnake,
+			expected: "db_password",
+		},
+		"snake PascalCase": {
+			name:     "ApiKey",
+			nameCase: nameCaseSnake,
+			expected: "api_key",
+		},
+		"snake acronym": {
+			name:     "AWSAccessKeyID",
+			nameCase: nameCaseSnake,
+			expected: "aws_access_key_id",
+		},
+		"snake digits": {
+			name:     "oauth2Token",
+			nameCase: nameCaseSnake,
+			expected: "oauth2_token",
+		},
+		"snake screaming snake case": {
+			name:     "DB_PASSWORD",
+			nameCase: nameCaseSnake,
+			expected: "db_password",
+		},
+		"snake mixed": {
+			name:     "db-Password_HASH",
+			nameCase: nameCaseSnake,
+			expected: "db_password_hash",
+		},
+		"kebab camelCase": {
+			name:     "dbPassword",
+			nameCase: nameCaseKebab,
+			expected: "db-password",
+		},
+		"kebab mixed": {
+			name:     "smtp_ServerHost-name",
+			nameCase: nameCaseKebab,
+			expected: "smtp-server-host-name",
+		},
+		"lower camelCase": {
+			name:     "dbPassword",
+			nameCase: nameCaseLower,
+			expected: "dbpassword",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := transformNameCase(tc.name, tc.nameCase)
+
+			assert.OK(t, err)
+			assert.Equal(t, actual, tc.expected)
+		})
+	}
+}
@@ -63,6 +63,13 @@ type Consumable interface {
 	String() string
 }
 
+// EnvConsumable is a Consumable that defines environment variables.
+type EnvConsumable interface {
+	Consumable
+	// EnvVars returns the names of the environment variables mapped to the full paths of the secrets they are sourced from.
+	EnvVars() map[string]string
+}
+
 // Parser can create a consumable from a config.
 // Each parser has a Type that must be unique.
 type Parser interface {
@@ -170,6 +177,11 @@ func (p *Presenter) Set(secrets map[string]api.SecretVersion) error {
 	return nil
 }
 
+// Consumables returns all consumables in the order in which they are defined in the spec.
+func (p *Presenter) Consumables() []Consumable {
+	return p.consumables
+}
+
 // Sources returns the full paths of all secrets sourced within the presenter.
 func (p *Presenter) Sources() map[string]struct{} {
 	total := make(map[string]struct{})
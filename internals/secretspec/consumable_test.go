@@ -139,3 +139,41 @@ secrets:
 		t.Error("did not get a ErrDuplicateConsumable for duplicate Inject consumable")
 	}
 }
+
+func TestPresenter_Consumables(t *testing.T) {
+	p, err := NewPresenter("./", true, DefaultParsers...)
+	assert.OK(t, err)
+
+	spec := []byte(
+		`
+secrets:
+    - env:
+        vars:
+            DB_USER: user/repo/db_user
+            DB_PASSWORD: user/repo/db_password
+    - env:
+        name: "other"
+        vars:
+            TOKEN: user/repo/token`)
+
+	err = p.Parse(spec)
+	assert.OK(t, err)
+
+	var vars []map[string]string
+	for _, c := range p.Consumables() {
+		envConsumable, ok := c.(EnvConsumable)
+		assert.Equal(t, ok, true)
+		vars = append(vars, envConsumable.EnvVars())
+	}
+
+	expected := []map[string]string{
+		{
+			"DB_USER":     "user/repo/db_user",
+			"DB_PASSWORD": "user/repo/db_password",
+		},
+		{
+			"TOKEN": "user/repo/token",
+		},
+	}
+	assert.Equal(t, vars, expected)
+}
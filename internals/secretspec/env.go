@@ -179,6 +179,15 @@ func (e *env) Sources() map[string]struct{} {
 	return sources
 }
 
+// EnvVars returns the names of the environment variables mapped to the full paths of the secrets they are sourced from.
+func (e *env) EnvVars() map[string]string {
+	vars := make(map[string]string, len(e.vars))
+	for _, v := range e.vars {
+		vars[v.target] = v.source
+	}
+	return vars
+}
+
 // Equals checks whether two envs have the same name.
 func (e *env) Equals(consumable Consumable) bool {
 	envConsumable, ok := consumable.(*env)